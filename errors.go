@@ -0,0 +1,30 @@
+/*
+Copyright ©  2024  M.Watermann, 10247 Berlin, Germany
+
+		All rights reserved
+	EMail : <support@mwat.de>
+*/
+package sortedlists
+
+import "errors"
+
+//lint:file-ignore ST1017 - I prefer Yoda conditions
+
+var (
+	// `ErrCapacityExceeded` is returned if an element was rejected
+	// because of a list's length limit (see `TSortedSlice.SetMaxLen()`).
+	ErrCapacityExceeded = errors.New("sortedlists: capacity exceeded")
+
+	// `ErrConflict` is returned if two maps hold different values for
+	// the same key (see `TSortedMap.MergeStrict()`).
+	ErrConflict = errors.New("sortedlists: conflicting values")
+
+	// `ErrDuplicate` is returned if an element is already present.
+	ErrDuplicate = errors.New("sortedlists: duplicate element")
+
+	// `ErrInvalidValue` is returned for values which can't be ordered
+	// or are rejected otherwise, e.g. floating-point `NaN`s.
+	ErrInvalidValue = errors.New("sortedlists: invalid value")
+)
+
+/* EoF */
@@ -1,3 +1,3 @@
 module github.com/mwat56/sortedlists
 
-go 1.22
+go 1.23
@@ -0,0 +1,157 @@
+/*
+Copyright ©  2024  M.Watermann, 10247 Berlin, Germany
+
+		All rights reserved
+	EMail : <support@mwat.de>
+*/
+package sortedlists
+
+import (
+	"cmp"
+	"container/list"
+	"sync"
+)
+
+//lint:file-ignore ST1017 - I prefer Yoda conditions
+
+type (
+	// `tLRU` keeps track of the access order of a map's keys
+	// (see `NewLRUMap()`).
+	//
+	// All methods may be called on a `nil` instance (i.e. a map not
+	// tracking the access order) and do nothing then.
+	//
+	// Since reading methods like `TSortedMap.Get()` update the access
+	// order while holding the map's read lock only, the list is guarded
+	// by its own mutex.
+	tLRU[K cmp.Ordered] struct {
+		mtx   sync.Mutex
+		order *list.List          // least recently used key at the front
+		pos   map[K]*list.Element // the list element of each key
+	}
+)
+
+// `newLRU()` creates a new, empty access order list.
+//
+// Returns:
+// - `*tLRU[K]`: A pointer to the newly created instance.
+func newLRU[K cmp.Ordered]() *tLRU[K] {
+	return &tLRU[K]{
+		order: list.New(),
+		pos:   make(map[K]*list.Element),
+	}
+} // newLRU()
+
+// `clone()` returns a copy of the access order restricted to the keys
+// accepted by `aFunc`.
+//
+// Parameters:
+// - `aFunc`: The function deciding whether a key should be copied.
+//
+// Returns:
+// - `*tLRU[K]`: The new access order list.
+func (lru *tLRU[K]) clone(aFunc func(K) bool) *tLRU[K] {
+	if nil == lru {
+		return nil
+	}
+	lru.mtx.Lock()
+	defer lru.mtx.Unlock()
+
+	result := newLRU[K]()
+	for elem := lru.order.Front(); nil != elem; elem = elem.Next() {
+		if key := elem.Value.(K); aFunc(key) {
+			result.pos[key] = result.order.PushBack(key)
+		}
+	}
+
+	return result
+} // clone()
+
+// `oldest()` returns the least recently used key.
+//
+// Returns:
+// - `K`: The least recently used key.
+// - `bool`: `true` if a key was found, or `false` if the list is empty.
+func (lru *tLRU[K]) oldest() (K, bool) {
+	var zero K // variable with its zero value
+	if nil == lru {
+		return zero, false
+	}
+	lru.mtx.Lock()
+	defer lru.mtx.Unlock()
+
+	if elem := lru.order.Front(); nil != elem {
+		return elem.Value.(K), true
+	}
+
+	return zero, false
+} // oldest()
+
+// `remove()` deletes `aKey` from the access order.
+//
+// Parameters:
+// - `aKey`: The key to remove.
+func (lru *tLRU[K]) remove(aKey K) {
+	if nil == lru {
+		return
+	}
+	lru.mtx.Lock()
+	defer lru.mtx.Unlock()
+
+	if elem, ok := lru.pos[aKey]; ok {
+		lru.order.Remove(elem)
+		delete(lru.pos, aKey)
+	}
+} // remove()
+
+// `rename()` replaces `aOldKey` by `aNewKey` keeping its position in
+// the access order.
+//
+// Parameters:
+// - `aOldKey`: The key to replace.
+// - `aNewKey`: The replacing key.
+func (lru *tLRU[K]) rename(aOldKey, aNewKey K) {
+	if nil == lru {
+		return
+	}
+	lru.mtx.Lock()
+	defer lru.mtx.Unlock()
+
+	if elem, ok := lru.pos[aOldKey]; ok {
+		delete(lru.pos, aOldKey)
+		elem.Value = aNewKey
+		lru.pos[aNewKey] = elem
+	}
+} // rename()
+
+// `reset()` empties the access order.
+func (lru *tLRU[K]) reset() {
+	if nil == lru {
+		return
+	}
+	lru.mtx.Lock()
+	defer lru.mtx.Unlock()
+
+	lru.order.Init()
+	clear(lru.pos)
+} // reset()
+
+// `touch()` marks `aKey` as the most recently used key.
+//
+// Parameters:
+// - `aKey`: The key accessed.
+func (lru *tLRU[K]) touch(aKey K) {
+	if nil == lru {
+		return
+	}
+	lru.mtx.Lock()
+	defer lru.mtx.Unlock()
+
+	if elem, ok := lru.pos[aKey]; ok {
+		lru.order.MoveToBack(elem)
+	} else {
+		lru.pos[aKey] = lru.order.PushBack(aKey)
+	}
+} // touch()
+
+/* EoF */
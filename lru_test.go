@@ -0,0 +1,120 @@
+/*
+Copyright ©  2024  M.Watermann, 10247 Berlin, Germany
+
+		All rights reserved
+	EMail : <support@mwat.de>
+*/
+package sortedlists
+
+import (
+	"slices"
+	"testing"
+)
+
+//lint:file-ignore ST1017 - I prefer Yoda conditions
+
+// `lruKeys()` returns the keys of `aLRU` from least to most recently used.
+func lruKeys(aLRU *tLRU[int]) []int {
+	result := []int{}
+	for elem := aLRU.order.Front(); nil != elem; elem = elem.Next() {
+		result = append(result, elem.Value.(int))
+	}
+
+	return result
+} // lruKeys()
+
+func Test_tLRU_touch(t *testing.T) {
+	lru := newLRU[int]()
+	for _, key := range []int{1, 2, 3} {
+		lru.touch(key)
+	}
+	lru.touch(1) // move to the back
+
+	if got, want := lruKeys(lru), []int{2, 3, 1}; !slices.Equal(want, got) {
+		t.Errorf("order = %v, want %v", got, want)
+	}
+	if got, ok := lru.oldest(); !ok || (2 != got) {
+		t.Errorf("oldest() = %d, %v, want 2, true", got, ok)
+	}
+	if got := len(lru.pos); 3 != got {
+		t.Errorf("len(pos) = %d, want 3", got)
+	}
+} // Test_tLRU_touch()
+
+func Test_tLRU_remove(t *testing.T) {
+	lru := newLRU[int]()
+	for _, key := range []int{1, 2, 3} {
+		lru.touch(key)
+	}
+	lru.remove(1)
+	lru.remove(99) // unknown keys are ignored
+
+	if got, want := lruKeys(lru), []int{2, 3}; !slices.Equal(want, got) {
+		t.Errorf("order = %v, want %v", got, want)
+	}
+	if _, ok := lru.pos[1]; ok {
+		t.Error("pos[1] still present after remove(1)")
+	}
+
+	lru.reset()
+	if got, ok := lru.oldest(); ok {
+		t.Errorf("oldest() after reset() = %d, true, want false", got)
+	}
+	if got := len(lru.pos); 0 != got {
+		t.Errorf("len(pos) after reset() = %d, want 0", got)
+	}
+} // Test_tLRU_remove()
+
+func Test_tLRU_rename(t *testing.T) {
+	lru := newLRU[int]()
+	for _, key := range []int{1, 2, 3} {
+		lru.touch(key)
+	}
+	lru.rename(2, 20)
+
+	if got, want := lruKeys(lru), []int{1, 20, 3}; !slices.Equal(want, got) {
+		t.Errorf("order = %v, want %v", got, want)
+	}
+	if _, ok := lru.pos[2]; ok {
+		t.Error("pos[2] still present after rename(2, 20)")
+	}
+	if _, ok := lru.pos[20]; !ok {
+		t.Error("pos[20] missing after rename(2, 20)")
+	}
+} // Test_tLRU_rename()
+
+func Test_tLRU_clone(t *testing.T) {
+	lru := newLRU[int]()
+	for _, key := range []int{4, 1, 3, 2} {
+		lru.touch(key)
+	}
+	odd := lru.clone(func(aKey int) bool { return 1 == aKey%2 })
+
+	if got, want := lruKeys(odd), []int{1, 3}; !slices.Equal(want, got) {
+		t.Errorf("clone() order = %v, want %v", got, want)
+	}
+
+	// The clone must be independent of the original.
+	odd.touch(1)
+	if got, want := lruKeys(lru), []int{4, 1, 3, 2}; !slices.Equal(want, got) {
+		t.Errorf("original order = %v, want %v", got, want)
+	}
+} // Test_tLRU_clone()
+
+func Test_tLRU_nil(t *testing.T) {
+	var lru *tLRU[int]
+
+	// None of these calls may panic.
+	lru.touch(1)
+	lru.rename(1, 2)
+	lru.remove(2)
+	lru.reset()
+	if nil != lru.clone(func(int) bool { return true }) {
+		t.Error("clone() of nil != nil")
+	}
+	if _, ok := lru.oldest(); ok {
+		t.Error("oldest() of nil = true, want false")
+	}
+} // Test_tLRU_nil()
+
+/* EoF */
@@ -0,0 +1,133 @@
+/*
+Copyright ©  2024  M.Watermann, 10247 Berlin, Germany
+
+		All rights reserved
+	EMail : <support@mwat.de>
+*/
+package sortedlists
+
+import (
+	"cmp"
+	"iter"
+	"slices"
+)
+
+//lint:file-ignore ST1017 - I prefer Yoda conditions
+
+type (
+	// `TReadOnlySortedMap` is an immutable view of a `TSortedMap`.
+	//
+	// It holds a frozen copy of the map's entries and provides only
+	// methods that don't modify the data, so read-only access is
+	// enforced at compile time. Since its data never changes no locking
+	// is required and all methods can be called concurrently.
+	TReadOnlySortedMap[K cmp.Ordered, V comparable] struct {
+		data map[K]V
+		keys []K
+	}
+)
+
+// --------------------------------------------------------------------------
+// methods of TReadOnlySortedMap
+
+// `All()` returns an iterator over all key/value pairs in sorted key order.
+//
+// Returns:
+// - `iter.Seq2[K, V]`: The iterator over the view's entries.
+func (ro TReadOnlySortedMap[K, V]) All() iter.Seq2[K, V] {
+	return func(aYield func(K, V) bool) {
+		for _, key := range ro.keys {
+			if !aYield(key, ro.data[key]) {
+				return
+			}
+		}
+	}
+} // All()
+
+// `Get()` retrieves a value by its key.
+//
+// Parameters:
+// - `aKey`: The key of the entry to be retrieved.
+//
+// Returns:
+// - `V`: The value associated with the `aKey`.
+// - `bool`: An indication whether the key was found in the view.
+func (ro TReadOnlySortedMap[K, V]) Get(aKey K) (V, bool) {
+	value, exists := ro.data[aKey]
+
+	return value, exists
+} // Get()
+
+// `Iterate()` allows iteration over the view in sorted key order.
+//
+// Parameters:
+//   - `aFunc`: A function that takes a key and its associated value as arguments and performs some operation on them.
+//
+// Returns:
+//   - `TReadOnlySortedMap[K, V]`: The view, allowing method chaining.
+func (ro TReadOnlySortedMap[K, V]) Iterate(aFunc func(K, V)) TReadOnlySortedMap[K, V] {
+	for _, key := range ro.keys {
+		aFunc(key, ro.data[key])
+	}
+
+	return ro
+} // Iterate()
+
+// `Keys()` returns a copy of the view's keys in sorted order.
+//
+// Returns:
+// - `[]K`: The sorted keys.
+func (ro TReadOnlySortedMap[K, V]) Keys() []K {
+	return append([]K{}, ro.keys...)
+} // Keys()
+
+// `Len()` returns the number of entries in the view.
+//
+// Returns:
+// - `int`: The view's length.
+func (ro TReadOnlySortedMap[K, V]) Len() int {
+	return len(ro.keys)
+} // Len()
+
+// `Range()` returns all entries with keys within the closed interval
+// `[aLow, aHigh]`.
+//
+// Parameters:
+// - `aLow`: The lower bound of the key interval.
+// - `aHigh`: The upper bound of the key interval.
+//
+// Returns:
+// - `[]TMapEntry[K, V]`: The entries within the interval in sorted key order.
+func (ro TReadOnlySortedMap[K, V]) Range(aLow, aHigh K) []TMapEntry[K, V] {
+	if emptyRange(aLow, aHigh) {
+		return []TMapEntry[K, V]{}
+	}
+
+	start, _ := slices.BinarySearch(ro.keys, aLow)
+	end, found := slices.BinarySearch(ro.keys, aHigh)
+	if found {
+		end++
+	}
+
+	result := make([]TMapEntry[K, V], 0, end-start)
+	for _, key := range ro.keys[start:end] {
+		result = append(result, TMapEntry[K, V]{Key: key, Value: ro.data[key]})
+	}
+
+	return result
+} // Range()
+
+// `Values()` returns the view's values in sorted key order.
+//
+// Returns:
+// - `[]V`: The values of all entries.
+func (ro TReadOnlySortedMap[K, V]) Values() []V {
+	result := make([]V, 0, len(ro.keys))
+	for _, key := range ro.keys {
+		result = append(result, ro.data[key])
+	}
+
+	return result
+} // Values()
+
+/* EoF */
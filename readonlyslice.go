@@ -0,0 +1,146 @@
+/*
+Copyright ©  2024  M.Watermann, 10247 Berlin, Germany
+
+		All rights reserved
+	EMail : <support@mwat.de>
+*/
+package sortedlists
+
+import (
+	"cmp"
+	"iter"
+	"slices"
+)
+
+//lint:file-ignore ST1017 - I prefer Yoda conditions
+
+type (
+	// `TReadOnlySortedSlice` is an immutable view of a `TSortedSlice`.
+	//
+	// It holds a frozen copy of the list's elements and provides only
+	// methods that don't modify the data, so read-only access is
+	// enforced at compile time. Since its data never changes no locking
+	// is required and all methods can be called concurrently.
+	TReadOnlySortedSlice[T cmp.Ordered] struct {
+		data []T
+	}
+)
+
+// --------------------------------------------------------------------------
+// methods of TReadOnlySortedSlice
+
+// `All()` returns an iterator over all elements in ascending order.
+//
+// Returns:
+// - `iter.Seq[T]`: The iterator over the view's elements.
+func (ro TReadOnlySortedSlice[T]) All() iter.Seq[T] {
+	return slices.Values(ro.data)
+} // All()
+
+// `Contains()` checks whether `aElement` is part of the view.
+//
+// Parameters:
+// - `aElement`: The element to look up.
+//
+// Returns:
+// - `bool`: `true` if `aElement` was found, or `false` otherwise.
+func (ro TReadOnlySortedSlice[T]) Contains(aElement T) bool {
+	_, exists := slices.BinarySearch(ro.data, aElement)
+
+	return exists
+} // Contains()
+
+// `Data()` returns a copy of the view's elements.
+//
+// Returns:
+// - `[]T`: The elements in ascending order.
+func (ro TReadOnlySortedSlice[T]) Data() []T {
+	return append([]T{}, ro.data...)
+} // Data()
+
+// `FindIndex()` returns the index of `aElement`.
+//
+// If the `aElement` is not found, the method returns -1.
+//
+// Parameters:
+// - `aElement`: The element to look up.
+//
+// Returns:
+// - `int`: The index of `aElement` in the view.
+func (ro TReadOnlySortedSlice[T]) FindIndex(aElement T) int {
+	if idx, exists := slices.BinarySearch(ro.data, aElement); exists {
+		return idx
+	}
+
+	return -1 // aElement not found
+} // FindIndex()
+
+// `Get()` retrieves an element by its index.
+//
+// Parameters:
+// - `aIndex`: The index to use for returning the element.
+//
+// Returns:
+// - `T`: The element at `aIndex`.
+// - `bool`: An indication whether the index was valid.
+func (ro TReadOnlySortedSlice[T]) Get(aIndex int) (T, bool) {
+	var result T // variable with its zero value
+
+	if (0 <= aIndex) && (aIndex < len(ro.data)) {
+		return ro.data[aIndex], true
+	}
+
+	return result, false
+} // Get()
+
+// `IterateReverse()` allows iteration over the view in reverse order.
+//
+// The iteration stops as soon as `aFunc` returns `false`.
+//
+// Parameters:
+// - `aFunc`: The function to call with each element.
+//
+// Returns:
+// - `TReadOnlySortedSlice[T]`: The view, allowing method chaining.
+func (ro TReadOnlySortedSlice[T]) IterateReverse(aFunc func(T) bool) TReadOnlySortedSlice[T] {
+	for idx := len(ro.data) - 1; 0 <= idx; idx-- {
+		if !aFunc(ro.data[idx]) {
+			break
+		}
+	}
+
+	return ro
+} // IterateReverse()
+
+// `Len()` returns the number of elements in the view.
+//
+// Returns:
+// - `int`: The view's length.
+func (ro TReadOnlySortedSlice[T]) Len() int {
+	return len(ro.data)
+} // Len()
+
+// `Range()` returns all elements within the closed interval
+// `[aLow, aHigh]`.
+//
+// Parameters:
+// - `aLow`: The lower bound of the interval.
+// - `aHigh`: The upper bound of the interval.
+//
+// Returns:
+// - `[]T`: A copy of the elements within the interval.
+func (ro TReadOnlySortedSlice[T]) Range(aLow, aHigh T) []T {
+	if emptyRange(aLow, aHigh) {
+		return []T{}
+	}
+
+	start, _ := slices.BinarySearch(ro.data, aLow)
+	end, found := slices.BinarySearch(ro.data, aHigh)
+	if found {
+		end++
+	}
+
+	return append([]T{}, ro.data[start:end]...)
+} // Range()
+
+/* EoF */
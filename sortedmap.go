@@ -7,11 +7,22 @@ Copyright © 2023, 2024  M.Watermann, 10247 Berlin, Germany
 package sortedlists
 
 import (
+	"bytes"
 	"cmp"
+	"context"
+	"database/sql/driver"
+	"encoding/json"
 	"fmt"
+	"io"
+	"iter"
+	"maps"
 	"slices"
 	"sort"
+	"strings"
 	"sync"
+	"time"
+	"unicode/utf8"
+	"unsafe"
 )
 
 //lint:file-ignore ST1017 - I prefer Yoda conditions
@@ -33,14 +44,130 @@ import (
 //
 // All methods are optionally thread-safe and can be called concurrently.
 type TSortedMap[K cmp.Ordered, V comparable] struct {
-	data map[K]V
-	keys []K
-	mtx  sync.RWMutex
-	safe bool
+	data   map[K]V
+	index  map[V][]K // optional reverse index (`nil` = disabled)
+	keys   []K
+	lru    *tLRU[K]        // optional access order (`nil` = disabled)
+	stamps map[K]time.Time // optional insertion times (`nil` = disabled)
+	mtx    sync.RWMutex
+	safe   bool
+}
+
+// `TMapEntry` represents a single key/value pair of a `TSortedMap`.
+type TMapEntry[K cmp.Ordered, V comparable] struct {
+	Key   K
+	Value V
 }
 
 // --------------------------------------------------------------------------
-// constructor function
+// constructor functions
+
+// `CollectMap()` creates a new `TSortedMap` from the pairs of `aSeq`.
+//
+// All pairs are inserted first and the list of keys is sorted just
+// once afterwards. If a key occurs more than once the last value wins.
+// Floating-point `NaN` keys are dropped.
+//
+// Parameters:
+//   - `aSeq`: The sequence providing the map's key/value pairs.
+//   - `aSafe`: Flag to decide whether the returned map should be
+//     thread safe, i.e. use a `sync.RWMutex` in all methods.
+//
+// Returns:
+// - `*TSortedMap[K, V]`: A pointer to the newly created instance.
+func CollectMap[K cmp.Ordered, V comparable](aSeq iter.Seq2[K, V], aSafe bool) *TSortedMap[K, V] {
+	sm := &TSortedMap[K, V]{
+		data: make(map[K]V),
+		safe: aSafe,
+	}
+	for key, value := range aSeq {
+		if !isNaN(key) {
+			sm.data[key] = value
+		}
+	}
+
+	sm.keys = make([]K, 0, len(sm.data))
+	for key := range sm.data {
+		sm.keys = append(sm.keys, key)
+	}
+	slices.Sort(sm.keys) // ascending
+
+	return sm
+} // CollectMap()
+
+// `NewExpiringMap()` creates a new instance of `TSortedMap` recording
+// the insertion time of each entry.
+//
+// The recorded times allow removing outdated entries by calling
+// `Purge()`, turning the map into a simple expiring cache. Updating an
+// entry's value by `Insert()` renews its insertion time. The price is
+// an additional internal map holding a timestamp per entry.
+//
+// Parameters:
+//   - `K`: The type of the keys in the sorted map.
+//   - `V`: The type of the values in the sorted map.
+//   - `aSafe`: Flag to decide whether the returned map should be
+//     thread safe, i.e. use a `sync.RWMutex` in all methods.
+//
+// Returns:
+// - `*TSortedMap[K, V]`: A pointer to the newly created instance.
+func NewExpiringMap[K cmp.Ordered, V comparable](aSafe bool) *TSortedMap[K, V] {
+	sm := NewMap[K, V](aSafe)
+	sm.stamps = make(map[K]time.Time)
+
+	return sm
+} // NewExpiringMap()
+
+// `NewIndexedMap()` creates a new instance of `TSortedMap` maintaining
+// a reverse index of its values.
+//
+// The reverse index maps each value to the sorted list of keys holding
+// it, so `FindIndex()` and `FindPositions()` don't need to scan all
+// entries. The price is additional memory of about one key per entry
+// plus the index's own map, and a slightly more expensive modification
+// of entries since the index has to be kept up to date. Use this
+// constructor for read-mostly maps with frequent value lookups only.
+//
+// Parameters:
+//   - `K`: The type of the keys in the sorted map.
+//   - `V`: The type of the values in the sorted map.
+//   - `aSafe`: Flag to decide whether the returned map should be
+//     thread safe, i.e. use a `sync.RWMutex` in all methods.
+//
+// Returns:
+// - `*TSortedMap[K, V]`: A pointer to the newly created instance.
+func NewIndexedMap[K cmp.Ordered, V comparable](aSafe bool) *TSortedMap[K, V] {
+	sm := NewMap[K, V](aSafe)
+	sm.index = make(map[V][]K)
+
+	return sm
+} // NewIndexedMap()
+
+// `NewLRUMap()` creates a new instance of `TSortedMap` additionally
+// tracking the access order of its entries.
+//
+// Each entry gets marked as most recently used when it's inserted or
+// updated (e.g. by `Insert()`) or read by `Get()`, so `EvictLRU()` can
+// remove the least recently used entry while the map's sorted key order
+// stays untouched. This turns the map into a key-sorted LRU cache.
+// The price is an additional linked list and internal map holding an
+// element per entry, and a short exclusive lock on that list for each
+// access (including `Get()`).
+//
+// Parameters:
+//   - `K`: The type of the keys in the sorted map.
+//   - `V`: The type of the values in the sorted map.
+//   - `aSafe`: Flag to decide whether the returned map should be
+//     thread safe, i.e. use a `sync.RWMutex` in all methods.
+//
+// Returns:
+// - `*TSortedMap[K, V]`: A pointer to the newly created instance.
+func NewLRUMap[K cmp.Ordered, V comparable](aSafe bool) *TSortedMap[K, V] {
+	sm := NewMap[K, V](aSafe)
+	sm.lru = newLRU[K]()
+
+	return sm
+} // NewLRUMap()
 
 // `NewMap()` creates a new instance of `TSortedMap` with the
 // specified key and value types.
@@ -64,12 +191,392 @@ func NewMap[K cmp.Ordered, V comparable](aSafe bool) *TSortedMap[K, V] {
 	}
 } // NewMap()
 
+// --------------------------------------------------------------------------
+// helper functions
+
+// `CompareMap()` compares the entries of both maps lexicographically.
+//
+// The entries are compared pairwise in sorted key order, first by key
+// and then by value; the first difference decides. If all entries of
+// the shorter map are equal to the respective entries of the longer
+// one, the shorter map is considered smaller. A `nil` map is smaller
+// than any other map.
+//
+// The read locks of both maps are acquired in a consistent order
+// (determined by their memory address) to rule out deadlocks.
+//
+// Parameters:
+// - `aMap1`: The first map to compare.
+// - `aMap2`: The second map to compare.
+//
+// Returns:
+// - `int`: -1 if `aMap1` is smaller, 0 if both are equal, and +1 if
+// `aMap1` is greater than `aMap2`.
+func CompareMap[K cmp.Ordered, V cmp.Ordered](aMap1, aMap2 *TSortedMap[K, V]) int {
+	switch {
+	case aMap1 == aMap2:
+		return 0
+	case nil == aMap1:
+		return -1
+	case nil == aMap2:
+		return 1
+	}
+
+	first, second := aMap1, aMap2
+	if uintptr(unsafe.Pointer(aMap2)) < uintptr(unsafe.Pointer(aMap1)) {
+		first, second = aMap2, aMap1
+	}
+	if first.safe {
+		first.mtx.RLock()
+		defer first.mtx.RUnlock()
+	}
+	if second.safe {
+		second.mtx.RLock()
+		defer second.mtx.RUnlock()
+	}
+
+	for idx := 0; (idx < len(aMap1.keys)) && (idx < len(aMap2.keys)); idx++ {
+		key1, key2 := aMap1.keys[idx], aMap2.keys[idx]
+		if result := cmp.Compare(key1, key2); 0 != result {
+			return result
+		}
+		if result := cmp.Compare(aMap1.data[key1], aMap2.data[key2]); 0 != result {
+			return result
+		}
+	}
+
+	return cmp.Compare(len(aMap1.keys), len(aMap2.keys))
+} // CompareMap()
+
+// `DiffIterate()` walks the keys of both maps in lockstep and calls
+// `aFunc` for each difference found, in ascending key order.
+//
+// The operation passed to `aFunc` is one of
+// - `"added"`: the key is present in `aNew` only (`aOld` is the zero value),
+// - `"removed"`: the key is present in `aOld` only (`aNew` is the zero value),
+// - `"changed"`: the key is present in both maps with different values.
+//
+// No diff lists are allocated; the walk stops as soon as `aFunc`
+// returns `false`.
+//
+// NOTE: For thread-safe maps the read locks are held during the whole
+// walk, so `aFunc` must not call any modifying methods of these maps.
+// The locks are acquired in a consistent order (determined by the maps'
+// memory address) to rule out deadlocks.
+//
+// Parameters:
+// - `aOld`: The previous version of the map.
+// - `aNew`: The current version of the map.
+// - `aFunc`: The function to call with each difference.
+func DiffIterate[K cmp.Ordered, V comparable](aOld, aNew *TSortedMap[K, V], aFunc func(aOp string, aKey K, aOld, aNew V) bool) {
+	if (nil == aOld) || (nil == aNew) || (aOld == aNew) {
+		return
+	}
+	first, second := aOld, aNew
+	if uintptr(unsafe.Pointer(aNew)) < uintptr(unsafe.Pointer(aOld)) {
+		first, second = aNew, aOld
+	}
+	if first.safe {
+		first.mtx.RLock()
+		defer first.mtx.RUnlock()
+	}
+	if second.safe {
+		second.mtx.RLock()
+		defer second.mtx.RUnlock()
+	}
+	var zero V // variable with its zero value
+
+	i, j := 0, 0
+	for (i < len(aOld.keys)) || (j < len(aNew.keys)) {
+		switch {
+		case (j == len(aNew.keys)) ||
+			((i < len(aOld.keys)) && (aOld.keys[i] < aNew.keys[j])):
+			key := aOld.keys[i]
+			if !aFunc("removed", key, aOld.data[key], zero) {
+				return
+			}
+			i++
+		case (i == len(aOld.keys)) || (aNew.keys[j] < aOld.keys[i]):
+			key := aNew.keys[j]
+			if !aFunc("added", key, zero, aNew.data[key]) {
+				return
+			}
+			j++
+		default: // equal keys
+			key := aOld.keys[i]
+			if oldVal, newVal := aOld.data[key], aNew.data[key]; oldVal != newVal {
+				if !aFunc("changed", key, oldVal, newVal) {
+					return
+				}
+			}
+			i++
+			j++
+		}
+	}
+} // DiffIterate()
+
+// `MapValues()` creates a new map with the keys of `aMap` and values
+// transformed by `aFunc`.
+//
+// This allows changing the value type, e.g. from `TSortedMap[string, int]`
+// to `TSortedMap[string, string]`. The keys are already sorted, so no
+// sorting is done. The returned map uses the same configuration as
+// `aMap` (thread-safety, reverse index, insertion times, access order).
+//
+// Parameters:
+// - `aMap`: The map to transform.
+// - `aFunc`: The function converting each value.
+//
+// Returns:
+// - `*TSortedMap[K, V2]`: The new map holding the transformed values.
+func MapValues[K cmp.Ordered, V1, V2 comparable](aMap *TSortedMap[K, V1], aFunc func(V1) V2) *TSortedMap[K, V2] {
+	if aMap.safe {
+		aMap.mtx.RLock()
+		defer aMap.mtx.RUnlock()
+	}
+
+	result := &TSortedMap[K, V2]{
+		data: make(map[K]V2, len(aMap.data)),
+		keys: append(make([]K, 0, len(aMap.keys)), aMap.keys...),
+		safe: aMap.safe,
+	}
+	for key, value := range aMap.data {
+		result.data[key] = aFunc(value)
+	}
+	if nil != aMap.index {
+		result.index = make(map[V2][]K)
+		result.reindex()
+	}
+	if nil != aMap.stamps {
+		result.stamps = maps.Clone(aMap.stamps)
+	}
+	result.lru = aMap.lru.clone(func(K) bool { return true })
+
+	return result
+} // MapValues()
+
+// `marshalKey()` returns the JSON member name for `aKey`.
+//
+// Non-string keys are formatted as JSON strings, e.g. the key `42`
+// becomes the member name `"42"`.
+//
+// Parameters:
+// - `aKey`: The key to encode.
+//
+// Returns:
+// - `[]byte`: The JSON encoded member name.
+// - `error`: A possible encoding error, or `nil`.
+func marshalKey[K cmp.Ordered](aKey K) ([]byte, error) {
+	name, err := json.Marshal(aKey)
+	if nil != err {
+		return nil, err
+	}
+	if '"' != name[0] { // numeric key: use its string representation
+		return json.Marshal(string(name))
+	}
+
+	return name, nil
+} // marshalKey()
+
+// `MaxValue()` returns the entry of `aMap` holding the largest value.
+//
+// Since the map is sorted by keys this requires a linear scan.
+// If several entries hold the largest value, the one with the smallest
+// key is returned.
+//
+// Parameters:
+// - `aMap`: The map to search.
+//
+// Returns:
+// - `K`: The key of the entry with the largest value.
+// - `V`: The largest value.
+// - `bool`: An indication whether the map held any entries.
+func MaxValue[K cmp.Ordered, V cmp.Ordered](aMap *TSortedMap[K, V]) (K, V, bool) {
+	return aMap.extremeValue(func(a, b V) bool { return a > b })
+} // MaxValue()
+
+// `mergeSorted()` merges two sorted lists into a new sorted list.
+//
+// Elements contained in both lists are added only once.
+//
+// Parameters:
+// - `aList1`: The first sorted list to merge.
+// - `aList2`: The second sorted list to merge.
+//
+// Returns:
+// - `[]T`: The merged list.
+func mergeSorted[T cmp.Ordered](aList1, aList2 []T) []T {
+	result := make([]T, 0, len(aList1)+len(aList2))
+
+	i, j := 0, 0
+	for (i < len(aList1)) && (j < len(aList2)) {
+		switch {
+		case aList1[i] < aList2[j]:
+			result = append(result, aList1[i])
+			i++
+		case aList2[j] < aList1[i]:
+			result = append(result, aList2[j])
+			j++
+		default: // equal elements
+			result = append(result, aList1[i])
+			i++
+			j++
+		}
+	}
+	result = append(result, aList1[i:]...)
+	result = append(result, aList2[j:]...)
+
+	return result
+} // mergeSorted()
+
+// `MinValue()` returns the entry of `aMap` holding the smallest value.
+//
+// Since the map is sorted by keys this requires a linear scan.
+// If several entries hold the smallest value, the one with the smallest
+// key is returned.
+//
+// Parameters:
+// - `aMap`: The map to search.
+//
+// Returns:
+// - `K`: The key of the entry with the smallest value.
+// - `V`: The smallest value.
+// - `bool`: An indication whether the map held any entries.
+func MinValue[K cmp.Ordered, V cmp.Ordered](aMap *TSortedMap[K, V]) (K, V, bool) {
+	return aMap.extremeValue(func(a, b V) bool { return a < b })
+} // MinValue()
+
+// `ReduceMap()` combines the entries of `aMap` in sorted key order into
+// a single accumulated value.
+//
+// NOTE: For a thread-safe map the read lock is held during the whole
+// iteration, so `aFunc` must not call any modifying methods of `aMap`.
+//
+// Parameters:
+// - `aMap`: The sorted map whose entries are to be folded.
+// - `aInit`: The initial value of the accumulator.
+// - `aFunc`: The function combining the accumulator and an entry.
+//
+// Returns:
+// - `A`: The final value of the accumulator.
+func ReduceMap[K cmp.Ordered, V comparable, A any](aMap *TSortedMap[K, V], aInit A, aFunc func(A, K, V) A) A {
+	if aMap.safe {
+		aMap.mtx.RLock()
+		defer aMap.mtx.RUnlock()
+	}
+
+	result := aInit
+	for _, key := range aMap.keys {
+		result = aFunc(result, key, aMap.data[key])
+	}
+
+	return result
+} // ReduceMap()
+
+// `SortedDistinctValues()` returns the values of `aMap` without
+// duplicates in ascending order.
+//
+// Parameters:
+// - `aMap`: The map whose values are to be collected.
+//
+// Returns:
+// - `[]V`: The sorted distinct values.
+func SortedDistinctValues[K cmp.Ordered, V cmp.Ordered](aMap *TSortedMap[K, V]) []V {
+	if aMap.safe {
+		aMap.mtx.RLock()
+		defer aMap.mtx.RUnlock()
+	}
+
+	result := aMap.distinctValues()
+	slices.Sort(result) // ascending
+
+	return result
+} // SortedDistinctValues()
+
+// `unmarshalKey()` returns the key represented by the JSON member
+// name `aName` as written by `marshalKey()`.
+//
+// Parameters:
+// - `aName`: The (unquoted) JSON member name.
+//
+// Returns:
+// - `K`: The decoded key.
+// - `error`: A possible decoding error, or `nil`.
+func unmarshalKey[K cmp.Ordered](aName string) (K, error) {
+	var key K
+
+	// First try a string key, then a numeric one.
+	quoted, _ := json.Marshal(aName)
+	if err := json.Unmarshal(quoted, &key); nil != err {
+		if err = json.Unmarshal([]byte(aName), &key); nil != err {
+			return key, fmt.Errorf("invalid key %q: %w", aName, err)
+		}
+	}
+
+	return key, nil
+} // unmarshalKey()
+
 // --------------------------------------------------------------------------
 // methods of TSortedMap
 
+// `All()` returns an iterator over all key/value pairs in sorted key order.
+//
+// The iterator works on the live map data instead of a copy, so
+// nothing gets allocated and breaking out of the loop early saves the
+// cost of copying the remaining entries.
+// The trade-off: for a thread-safe map the read lock is held while
+// iterating, hence the loop body must not call any modifying methods
+// of this map (which would cause a deadlock). For a map which isn't
+// thread-safe, modifications inside the loop lead to undefined results.
+// Use `Keys()` if a snapshot is required.
+//
+// Returns:
+// - `iter.Seq2[K, V]`: The iterator over the map's entries.
+func (sm *TSortedMap[K, V]) All() iter.Seq2[K, V] {
+	return func(aYield func(K, V) bool) {
+		if sm.safe {
+			sm.mtx.RLock()
+			defer sm.mtx.RUnlock()
+		}
+
+		for _, key := range sm.keys {
+			if !aYield(key, sm.data[key]) {
+				return
+			}
+		}
+	}
+} // All()
+
+// `Backward()` returns an iterator over all key/value pairs in reverse
+// sorted key order.
+//
+// Like `All()` the iterator works on the live map data, with the same
+// restrictions regarding modifications while iterating.
+//
+// Returns:
+// - `iter.Seq2[K, V]`: The iterator over the map's entries.
+func (sm *TSortedMap[K, V]) Backward() iter.Seq2[K, V] {
+	return func(aYield func(K, V) bool) {
+		if sm.safe {
+			sm.mtx.RLock()
+			defer sm.mtx.RUnlock()
+		}
+
+		for idx := len(sm.keys) - 1; 0 <= idx; idx-- {
+			key := sm.keys[idx]
+			if !aYield(key, sm.data[key]) {
+				return
+			}
+		}
+	}
+} // Backward()
+
 // `Clear()` empties the internal data structures:
 // all map entries are removed.
 //
+// The capacity of the internal structures is kept, so refilling the
+// map doesn't require new allocations.
+//
 // Returns:
 // - `*TSortedMap`: The cleared hash map.
 func (sm *TSortedMap[K, V]) Clear() *TSortedMap[K, V] {
@@ -78,23 +585,133 @@ func (sm *TSortedMap[K, V]) Clear() *TSortedMap[K, V] {
 		defer sm.mtx.Unlock()
 	}
 
-	sm.data = make(map[K]V)
-	sm.keys = make([]K, 0)
+	sm.clear()
 
 	return sm
 } // Clear()
 
+// `clear()` removes all entries while keeping the capacity of the
+// internal data structures.
+func (sm *TSortedMap[K, V]) clear() {
+	if nil == sm.data {
+		sm.data = make(map[K]V)
+	} else {
+		clear(sm.data)
+	}
+	clear(sm.keys) // release the keys' memory
+	sm.keys = sm.keys[:0]
+	if nil != sm.index {
+		clear(sm.index)
+	}
+	if nil != sm.stamps {
+		clear(sm.stamps)
+	}
+	sm.lru.reset()
+} // clear()
+
+// `Clone()` returns a copy of the current map.
+//
+// The copy holds its own copy of the entries and uses the same
+// configuration (e.g. thread-safety) as the current map, so it behaves
+// identically without sharing any data with the original.
+// The keys get a freshly allocated backing array, so adding keys to
+// either map never affects the other one.
+//
+// Returns:
+// - `*TSortedMap[K, V]`: The new map instance.
+func (sm *TSortedMap[K, V]) Clone() *TSortedMap[K, V] {
+	if sm.safe {
+		sm.mtx.RLock()
+		defer sm.mtx.RUnlock()
+	}
+
+	// Size both structures up front to avoid incremental growth.
+	result := &TSortedMap[K, V]{
+		data: make(map[K]V, len(sm.data)),
+		keys: append(make([]K, 0, len(sm.keys)), sm.keys...),
+		safe: sm.safe,
+	}
+	for key, value := range sm.data {
+		result.data[key] = value
+	}
+	if nil != sm.index {
+		result.index = make(map[V][]K, len(sm.index))
+		for value, keys := range sm.index {
+			result.index[value] = append(make([]K, 0, len(keys)), keys...)
+		}
+	}
+	if nil != sm.stamps {
+		result.stamps = maps.Clone(sm.stamps)
+	}
+	result.lru = sm.lru.clone(func(K) bool { return true })
+
+	return result
+} // Clone()
+
+// `ContainsAllKeys()` checks whether all of `aKeys` are present in the map.
+//
+// All lookups are done while holding the read lock only once; the
+// check stops at the first missing key. An empty list of keys is
+// trivially contained, hence the result is `true`.
+//
+// Parameters:
+// - `aKeys`: The keys to look up.
+//
+// Returns:
+// - `bool`: `true` if all keys were found, or `false` otherwise.
+func (sm *TSortedMap[K, V]) ContainsAllKeys(aKeys ...K) bool {
+	if sm.safe {
+		sm.mtx.RLock()
+		defer sm.mtx.RUnlock()
+	}
+
+	for _, key := range aKeys {
+		if _, exists := sm.data[key]; !exists {
+			return false
+		}
+	}
+
+	return true
+} // ContainsAllKeys()
+
+// `ContainsAnyKeys()` checks whether at least one of `aKeys` is present
+// in the map.
+//
+// All lookups are done while holding the read lock only once; the
+// check stops at the first key found. For an empty list of keys the
+// result is `false`.
+//
+// Parameters:
+// - `aKeys`: The keys to look up.
+//
+// Returns:
+// - `bool`: `true` if any key was found, or `false` otherwise.
+func (sm *TSortedMap[K, V]) ContainsAnyKeys(aKeys ...K) bool {
+	if sm.safe {
+		sm.mtx.RLock()
+		defer sm.mtx.RUnlock()
+	}
+
+	for _, key := range aKeys {
+		if _, exists := sm.data[key]; exists {
+			return true
+		}
+	}
+
+	return false
+} // ContainsAnyKeys()
+
 func (sm *TSortedMap[K, V]) delete(aKey K) bool {
 	// Check if the key actually exists
-	if _, exists := sm.data[aKey]; exists {
+	if value, exists := sm.data[aKey]; exists {
 		delete(sm.data, aKey)
+		delete(sm.stamps, aKey)
+		sm.lru.remove(aKey)
+		sm.indexRemove(aKey, value)
 
 		// Update the keys slice
-		for idx, key := range sm.keys {
-			if key == aKey {
-				sm.keys = append(sm.keys[:idx], sm.keys[idx+1:]...)
-				break
-			}
+		if idx, found := slices.BinarySearch(sm.keys, aKey); found {
+			sm.keys = slices.Delete(sm.keys, idx, idx+1)
 		}
 		return true
 	}
@@ -118,283 +735,1939 @@ func (sm *TSortedMap[K, V]) Delete(aKey K) bool {
 	return sm.delete(aKey)
 } // Delete()
 
-func (sm *TSortedMap[K, V]) equals(aMap *TSortedMap[K, V]) bool {
-	// Check if the maps have the same number of elements
-	if len(sm.data) != len(aMap.data) {
-		return false
+// `DeleteRangeFunc()` removes all entries with keys within the closed
+// interval `[aLow, aHigh]` for which `aFunc` returns `true`.
+//
+// Only the entries within the interval are passed to `aFunc` (which
+// must not modify the map), and the list of keys is rebuilt just once.
+//
+// Parameters:
+// - `aLow`: The lower bound of the key interval.
+// - `aHigh`: The upper bound of the key interval.
+// - `aFunc`: The function deciding whether an entry should be deleted.
+//
+// Returns:
+// - `int`: The number of entries removed.
+func (sm *TSortedMap[K, V]) DeleteRangeFunc(aLow, aHigh K, aFunc func(K, V) bool) int {
+	if (nil == aFunc) || emptyRange(aLow, aHigh) {
+		return 0
+	}
+	if sm.safe {
+		sm.mtx.Lock()
+		defer sm.mtx.Unlock()
+	}
+
+	start, _ := slices.BinarySearch(sm.keys, aLow)
+	end, found := slices.BinarySearch(sm.keys, aHigh)
+	if found {
+		end++
+	}
+
+	var result int
+	for _, key := range sm.keys[start:end] {
+		if value := sm.data[key]; aFunc(key, value) {
+			delete(sm.data, key)
+			delete(sm.stamps, key)
+			sm.lru.remove(key)
+			sm.indexRemove(key, value)
+			result++
+		}
+	}
+
+	if 0 < result {
+		// Compact the interval in place and close the resulting gap
+		kept := slices.DeleteFunc(sm.keys[start:end], func(aKey K) bool {
+			_, exists := sm.data[aKey]
+			return !exists
+		})
+		sm.keys = slices.Delete(sm.keys, start+len(kept), end)
+	}
+
+	return result
+} // DeleteRangeFunc()
+
+// `DistinctValues()` returns the map's values without duplicates.
+//
+// The values are returned in the order of their first occurrence in
+// sorted key order. Use `SortedDistinctValues()` for maps with ordered
+// values to get them sorted.
+//
+// Returns:
+// - `[]V`: The distinct values.
+func (sm *TSortedMap[K, V]) DistinctValues() []V {
+	if sm.safe {
+		sm.mtx.RLock()
+		defer sm.mtx.RUnlock()
+	}
+
+	return sm.distinctValues()
+} // DistinctValues()
+
+func (sm *TSortedMap[K, V]) distinctValues() []V {
+	seen := make(map[V]struct{}, len(sm.data))
+	result := make([]V, 0, len(sm.data))
+	for _, key := range sm.keys {
+		value := sm.data[key]
+		if _, exists := seen[value]; !exists {
+			seen[value] = struct{}{}
+			result = append(result, value)
+		}
+	}
+
+	return result
+} // distinctValues()
+
+// `Drain()` returns all entries and empties the map.
+//
+// Both operations are done while holding the write lock, so each entry
+// is returned exactly once even if other goroutines concurrently add
+// new entries.
+//
+// Returns:
+// - `[]TMapEntry[K, V]`: All key/value pairs in sorted key order.
+func (sm *TSortedMap[K, V]) Drain() []TMapEntry[K, V] {
+	if sm.safe {
+		sm.mtx.Lock()
+		defer sm.mtx.Unlock()
+	}
+
+	result := make([]TMapEntry[K, V], 0, len(sm.keys))
+	for _, key := range sm.keys {
+		result = append(result, TMapEntry[K, V]{Key: key, Value: sm.data[key]})
+	}
+	sm.data = make(map[K]V)
+	sm.keys = make([]K, 0)
+	sm.reindex()
+	if nil != sm.stamps {
+		sm.stamps = make(map[K]time.Time)
+	}
+	sm.lru.reset()
+
+	return result
+} // Drain()
+
+// `Each()` calls `aFunc` for each entry in sorted key order and stops
+// at the first error returned.
+//
+// A snapshot of the entries is taken while holding the read lock, so
+// the lock isn't held while calling `aFunc`. Hence `aFunc` may take its
+// time (e.g. doing I/O) and may even modify the map; such modifications
+// don't affect the current iteration.
+//
+// Parameters:
+// - `aFunc`: The function to call with each key/value pair.
+//
+// Returns:
+// - `error`: The first error returned by `aFunc`, or `nil`.
+func (sm *TSortedMap[K, V]) Each(aFunc func(K, V) error) error {
+	var entries []TMapEntry[K, V]
+
+	func() {
+		if sm.safe {
+			sm.mtx.RLock()
+			defer sm.mtx.RUnlock()
+		}
+
+		entries = make([]TMapEntry[K, V], 0, len(sm.keys))
+		for _, key := range sm.keys {
+			entries = append(entries, TMapEntry[K, V]{Key: key, Value: sm.data[key]})
+		}
+	}()
+
+	for _, entry := range entries {
+		if err := aFunc(entry.Key, entry.Value); nil != err {
+			return err
+		}
+	}
+
+	return nil
+} // Each()
+
+// `EntriesRange()` returns the entries at the positions `[aStart, aEnd)`
+// of the map's sorted key order.
+//
+// Both positions are clamped to the range between zero and the number
+// of entries, which makes this method suitable for windowed access to
+// large maps (e.g. paging).
+//
+// Parameters:
+// - `aStart`: The position of the first entry to return.
+// - `aEnd`: The position after the last entry to return.
+//
+// Returns:
+// - `[]TMapEntry[K, V]`: A copy of the respective key/value pairs.
+func (sm *TSortedMap[K, V]) EntriesRange(aStart, aEnd int) []TMapEntry[K, V] {
+	if sm.safe {
+		sm.mtx.RLock()
+		defer sm.mtx.RUnlock()
+	}
+
+	aStart = max(0, min(aStart, len(sm.keys)))
+	aEnd = max(aStart, min(aEnd, len(sm.keys)))
+
+	result := make([]TMapEntry[K, V], 0, aEnd-aStart)
+	for _, key := range sm.keys[aStart:aEnd] {
+		result = append(result, TMapEntry[K, V]{Key: key, Value: sm.data[key]})
+	}
+
+	return result
+} // EntriesRange()
+
+func (sm *TSortedMap[K, V]) equals(aMap *TSortedMap[K, V]) bool {
+	// Check if the maps have the same number of elements
+	if len(sm.data) != len(aMap.data) {
+		return false
+	}
+
+	// Check if the keys are in the same order
+	if !slices.Equal(sm.keys, aMap.keys) {
+		return false
+	}
+
+	// Check if the values are equal for each key
+	for key, val1 := range sm.data {
+		if val2, ok := aMap.data[key]; !ok || val1 != val2 {
+			return false
+		}
+	}
+
+	return true
+} // equals()
+
+func (sm *TSortedMap[K, V]) Equals(aMap *TSortedMap[K, V]) bool {
+	if sm.safe {
+		sm.mtx.RLock()
+		defer sm.mtx.RUnlock()
+	}
+	if aMap.safe {
+		aMap.mtx.RLock()
+		defer aMap.mtx.RUnlock()
+	}
+
+	return sm.equals(aMap)
+} // Equals()
+
+// `EvictLRU()` removes the least recently used entry and returns it.
+//
+// Only maps created by `NewLRUMap()` track the access order of their
+// entries; for all other maps this method does nothing.
+//
+// Returns:
+// - `K`: The key of the removed entry.
+// - `V`: The value of the removed entry.
+// - `bool`: `true` if an entry was removed, or `false` otherwise.
+func (sm *TSortedMap[K, V]) EvictLRU() (K, V, bool) {
+	if sm.safe {
+		sm.mtx.Lock()
+		defer sm.mtx.Unlock()
+	}
+
+	var value V // variable with its zero value
+	key, ok := sm.lru.oldest()
+	if !ok {
+		return key, value, false
+	}
+	value = sm.data[key]
+	sm.delete(key)
+
+	return key, value, true
+} // EvictLRU()
+
+func (sm *TSortedMap[K, V]) extremeValue(aBetter func(a, b V) bool) (rKey K, rValue V, rOK bool) {
+	if sm.safe {
+		sm.mtx.RLock()
+		defer sm.mtx.RUnlock()
+	}
+
+	for idx, key := range sm.keys {
+		if value := sm.data[key]; (0 == idx) || aBetter(value, rValue) {
+			rKey, rValue, rOK = key, value, true
+		}
+	}
+
+	return
+} // extremeValue()
+
+func (sm *TSortedMap[K, V]) findIndex(aValue V) []K {
+	if nil != sm.index {
+		if keys, ok := sm.index[aValue]; ok {
+			return append([]K{}, keys...)
+		}
+		return nil
+	}
+	var result []K
+
+	for _, key := range sm.keys {
+		if sm.data[key] == aValue {
+			result = append(result, key)
+		}
+	}
+
+	return result
+} // findIndex()
+
+// `FindIndex()` returns a slice of keys that have the given value.
+//
+// For a map created by `NewIndexedMap()` the keys are taken from the
+// reverse index, otherwise all entries are scanned.
+//
+// Parameters:
+// - `aValue`: The element to look up.
+//
+// Returns:
+// - `[]K`: The index of `aID` in the list.
+func (sm *TSortedMap[K, V]) FindIndex(aValue V) []K {
+	if sm.safe {
+		sm.mtx.RLock()
+		defer sm.mtx.RUnlock()
+	}
+
+	return sm.findIndex(aValue)
+} // FindIndex()
+
+// `FindPositions()` returns the positions of all entries holding
+// `aValue`.
+//
+// Unlike `FindIndex()` this method returns the entries' indices in
+// sorted key order instead of their keys.
+//
+// Parameters:
+// - `aValue`: The value to look up.
+//
+// Returns:
+// - `[]int`: The ascending positions of the matching entries.
+func (sm *TSortedMap[K, V]) FindPositions(aValue V) []int {
+	if sm.safe {
+		sm.mtx.RLock()
+		defer sm.mtx.RUnlock()
+	}
+	var result []int
+
+	if nil != sm.index {
+		for _, key := range sm.index[aValue] {
+			idx, _ := slices.BinarySearch(sm.keys, key)
+			result = append(result, idx)
+		}
+		return result
+	}
+
+	for idx, key := range sm.keys {
+		if sm.data[key] == aValue {
+			result = append(result, idx)
+		}
+	}
+
+	return result
+} // FindPositions()
+
+// `ForEachIndex()` iterates over the map in sorted key order, passing
+// each entry's position as well.
+//
+// The iteration stops as soon as `aFunc` returns `false`.
+//
+// NOTE: For a thread-safe map the read lock is held during the whole
+// iteration, so `aFunc` must not call any modifying methods of this map.
+// Otherwise a deadlock might occur.
+//
+// Parameters:
+// - `aFunc`: The function to call with each entry's index, key, and value.
+//
+// Returns:
+// - `*TSortedMap[K, V]`: The map instance, allowing method chaining.
+func (sm *TSortedMap[K, V]) ForEachIndex(aFunc func(aIndex int, aKey K, aValue V) bool) *TSortedMap[K, V] {
+	if sm.safe {
+		sm.mtx.RLock()
+		defer sm.mtx.RUnlock()
+	}
+
+	for idx, key := range sm.keys {
+		if !aFunc(idx, key, sm.data[key]) {
+			break
+		}
+	}
+
+	return sm
+} // ForEachIndex()
+
+// `Get()` retrieves a value by its key from the SortedMap
+//
+// Parameters:
+// - `aKey`: The key of the entry to be retrieved.
+//
+// Returns:
+// - `V`: The value associated with the `aKey`.
+// - `bool`: An indication whether the key was found in the map.
+func (sm *TSortedMap[K, V]) Get(aKey K) (V, bool) {
+	if sm.safe {
+		sm.mtx.RLock()
+		defer sm.mtx.RUnlock()
+	}
+
+	value, exists := sm.data[aKey]
+	if exists {
+		sm.lru.touch(aKey)
+	}
+
+	return value, exists
+} // Get()
+
+// `GetMany()` retrieves several values by their keys from the SortedMap.
+//
+// All lookups are done while holding the read lock only once, hence the
+// returned values represent a consistent snapshot of the map.
+// Keys not present in the map yield the zero value and `false`.
+//
+// Parameters:
+// - `aKeys`: The keys of the entries to be retrieved.
+//
+// Returns:
+// - `[]V`: The values associated with the `aKeys`.
+// - `[]bool`: Indications whether the respective key was found in the map.
+func (sm *TSortedMap[K, V]) GetMany(aKeys ...K) ([]V, []bool) {
+	if sm.safe {
+		sm.mtx.RLock()
+		defer sm.mtx.RUnlock()
+	}
+	values := make([]V, len(aKeys))
+	found := make([]bool, len(aKeys))
+
+	for idx, key := range aKeys {
+		values[idx], found[idx] = sm.data[key]
+	}
+
+	return values, found
+} // GetMany()
+
+// `GetWithIndex()` retrieves a value by its key together with the key's
+// position in sorted key order.
+//
+// Both are determined while holding the read lock just once, so they
+// are consistent with each other.
+//
+// Parameters:
+// - `aKey`: The key of the entry to be retrieved.
+//
+// Returns:
+// - `V`: The value associated with the `aKey`.
+// - `int`: The index of `aKey` in sorted key order, or -1 if not found.
+// - `bool`: An indication whether the key was found in the map.
+func (sm *TSortedMap[K, V]) GetWithIndex(aKey K) (V, int, bool) {
+	if sm.safe {
+		sm.mtx.RLock()
+		defer sm.mtx.RUnlock()
+	}
+	var result V // variable with its zero value
+
+	idx, found := slices.BinarySearch(sm.keys, aKey)
+	if !found {
+		return result, -1, false
+	}
+
+	return sm.data[aKey], idx, true
+} // GetWithIndex()
+
+// `KeySet()` returns the map's keys as a standard Go set.
+//
+// Returns:
+// - `map[K]struct{}`: A set holding all keys of the map.
+func (sm *TSortedMap[K, V]) KeySet() map[K]struct{} {
+	if sm.safe {
+		sm.mtx.RLock()
+		defer sm.mtx.RUnlock()
+	}
+
+	result := make(map[K]struct{}, len(sm.keys))
+	for _, key := range sm.keys {
+		result[key] = struct{}{}
+	}
+
+	return result
+} // KeySet()
+
+// Keys returns a slice of all keys in sorted order
+
+// `Keys()` returns a slice of all keys in sorted order
+//
+// This method returns a slice of all keys in the map in sorted order.
+//
+// The returned slice is a fresh copy owned by the caller.
+//
+// Returns:
+// - `[]K`: A slice of keys in the sorted map.
+func (sm *TSortedMap[K, V]) Keys() []K {
+	if sm.safe {
+		sm.mtx.RLock()
+		defer sm.mtx.RUnlock()
+	}
+
+	return append(make([]K, 0, len(sm.keys)), sm.keys...)
+} // Keys()
+
+// `KeysAndValues()` returns all keys and their values in sorted key
+// order.
+//
+// Both lists are captured while holding the read lock just once, so
+// they correspond element by element, i.e. `values[i]` is the value of
+// `keys[i]`, even if other goroutines modify the map concurrently.
+//
+// Returns:
+// - `[]K`: A slice of keys in the sorted map.
+// - `[]V`: A slice of the respective values.
+func (sm *TSortedMap[K, V]) KeysAndValues() ([]K, []V) {
+	if sm.safe {
+		sm.mtx.RLock()
+		defer sm.mtx.RUnlock()
+	}
+
+	keys := make([]K, len(sm.keys))
+	values := make([]V, len(sm.keys))
+	for idx, key := range sm.keys {
+		keys[idx], values[idx] = key, sm.data[key]
+	}
+
+	return keys, values
+} // KeysAndValues()
+
+// `KeysBatched()` calls `aFunc` with successive batches of up to `aSize`
+// keys in sorted order.
+//
+// A private snapshot of the keys is taken under the read lock while
+// `aFunc` is called without holding any lock, so it may safely call
+// other methods of the map. The processing stops as soon as `aFunc`
+// returns `false`. The batches are parts of that snapshot, so modifying
+// them doesn't affect the map.
+//
+// Parameters:
+// - `aSize`: The maximum number of keys per batch; must be greater than zero.
+// - `aFunc`: The function to call with each batch.
+//
+// Returns:
+// - `*TSortedMap[K, V]`: The current map.
+func (sm *TSortedMap[K, V]) KeysBatched(aSize int, aFunc func([]K) bool) *TSortedMap[K, V] {
+	if (0 >= aSize) || (nil == aFunc) {
+		return sm
+	}
+
+	keys := sm.Keys()
+	for start := 0; start < len(keys); start += aSize {
+		end := min(start+aSize, len(keys))
+		if !aFunc(keys[start:end:end]) {
+			break
+		}
+	}
+
+	return sm
+} // KeysBatched()
+
+// `KeysOrdered()` returns a slice of all keys in ascending or
+// descending order.
+//
+// Like `Keys()` the returned slice is a fresh copy owned by the caller.
+//
+// Parameters:
+// - `aDescending`: Whether to return the keys in descending order.
+//
+// Returns:
+// - `[]K`: A slice of keys in the requested order.
+func (sm *TSortedMap[K, V]) KeysOrdered(aDescending bool) []K {
+	if sm.safe {
+		sm.mtx.RLock()
+		defer sm.mtx.RUnlock()
+	}
+
+	if !aDescending {
+		return append(make([]K, 0, len(sm.keys)), sm.keys...)
+	}
+
+	result := make([]K, 0, len(sm.keys))
+	for idx := len(sm.keys) - 1; 0 <= idx; idx-- {
+		result = append(result, sm.keys[idx])
+	}
+
+	return result
+} // KeysOrdered()
+
+// `KeysChan()` streams the map's keys in sorted order over a channel.
+//
+// A snapshot of the keys is taken while holding the read lock, so the
+// lock isn't held while sending. The returned channel is closed after
+// the last key was sent or when `aCtx` got cancelled.
+//
+// Parameters:
+// - `aCtx`: The context to stop the streaming early.
+//
+// Returns:
+// - `<-chan K`: The channel delivering the map's keys.
+func (sm *TSortedMap[K, V]) KeysChan(aCtx context.Context) <-chan K {
+	keys := sm.Keys()
+	result := make(chan K)
+
+	go func() {
+		defer close(result)
+
+		for _, key := range keys {
+			select {
+			case <-aCtx.Done():
+				return
+			case result <- key:
+			}
+		}
+	}()
+
+	return result
+} // KeysChan()
+
+// `KeysEqual()` checks whether the map's keys equal `aExpected`.
+//
+// The keys are compared element by element in their sorted order, so
+// this method also verifies the map's key ordering.
+//
+// Parameters:
+// - `aExpected`: The list of keys to compare with.
+//
+// Returns:
+// - `bool`: `true` if both lists are equal, or `false` otherwise.
+func (sm *TSortedMap[K, V]) KeysEqual(aExpected []K) bool {
+	if sm.safe {
+		sm.mtx.RLock()
+		defer sm.mtx.RUnlock()
+	}
+
+	return slices.Equal(sm.keys, aExpected)
+} // KeysEqual()
+
+// `indexAdd()` records `aKey` as holding `aValue` in the reverse index.
+//
+// Parameters:
+// - `aKey`: The key of the entry.
+// - `aValue`: The value of the entry.
+func (sm *TSortedMap[K, V]) indexAdd(aKey K, aValue V) {
+	if nil == sm.index {
+		return
+	}
+
+	keys := sm.index[aValue]
+	if idx, found := slices.BinarySearch(keys, aKey); !found {
+		sm.index[aValue] = slices.Insert(keys, idx, aKey)
+	}
+} // indexAdd()
+
+// `indexRemove()` removes `aKey` from the reverse index of `aValue`.
+//
+// Parameters:
+// - `aKey`: The key of the entry.
+// - `aValue`: The value of the entry.
+func (sm *TSortedMap[K, V]) indexRemove(aKey K, aValue V) {
+	if nil == sm.index {
+		return
+	}
+
+	keys := sm.index[aValue]
+	if idx, found := slices.BinarySearch(keys, aKey); found {
+		if 1 == len(keys) {
+			delete(sm.index, aValue)
+		} else {
+			sm.index[aValue] = slices.Delete(keys, idx, idx+1)
+		}
+	}
+} // indexRemove()
+
+func (sm *TSortedMap[K, V]) insert(aKey K, aValue V) bool {
+	if isNaN(aKey) { // NaN would corrupt the key ordering
+		return false
+	}
+	if oldValue, exists := sm.data[aKey]; exists {
+		sm.data[aKey] = aValue
+		sm.indexRemove(aKey, oldValue)
+		sm.indexAdd(aKey, aValue)
+		sm.stamp(aKey)
+		sm.lru.touch(aKey)
+
+		return true
+	}
+
+	// There are different situations to consider:
+	// 1: the key-list is empty,
+	// 2: the key-list doesn't already contain the key,
+	// 3: the key-list contains the key but with a different value
+	sLen := len(sm.keys)
+	if 0 == sLen {
+		// 1: empty list: just add the new item
+		sm.keys = append(sm.keys, aKey)
+	} else {
+		// find the insertion index using binary search
+		idx := sort.Search(sLen, func(i int) bool {
+			return sm.keys[i] >= aKey
+		})
+
+		if sLen == idx {
+			// 2: key not found: add key at the end
+			sm.keys = append(sm.keys, aKey)
+		} else if (sm.keys)[idx] != aKey {
+			// 3: the search index doesn't point to the required key
+			sm.keys = append(sm.keys, aKey)
+			copy((sm.keys)[idx+1:], (sm.keys)[idx:])
+			(sm.keys)[idx] = aKey
+		} else {
+			// dummy instruction for debugger
+			sLen = 0
+		}
+	}
+	sm.data[aKey] = aValue
+	sm.indexAdd(aKey, aValue)
+	sm.stamp(aKey)
+	sm.lru.touch(aKey)
+
+	return true
+} // insert()
+
+// `Insert()` adds or updates a key/value pair in the sorted map.
+//
+// A floating-point `NaN` key is rejected since it can't be ordered.
+//
+// Parameters:
+// - `aKey`: The key of the entry to be added or updated.
+// - `aValue`: The value to be associated with the key.
+//
+// Returns:
+// - `bool`: `true` if `aID` was inserted, or `false` otherwise.
+func (sm *TSortedMap[K, V]) Insert(aKey K, aValue V) bool {
+	if sm.safe {
+		sm.mtx.Lock()
+		defer sm.mtx.Unlock()
+	}
+
+	return sm.insert(aKey, aValue)
+} // Insert()
+
+// `InsertAt()` adds or updates a key/value pair like `Insert()` and
+// returns the entry's position in sorted key order.
+//
+// This allows coordinating a producer with a consumer walking the map
+// position by position (e.g. by `ForEachIndex()` in batches): a newly
+// added entry whose index is less than or equal to the consumer's
+// current position shifts all following entries by one and won't be
+// seen by the consumer, while an entry behind that position will be.
+//
+// Parameters:
+// - `aKey`: The key of the entry to be added or updated.
+// - `aValue`: The value to be associated with the key.
+//
+// Returns:
+// - `int`: The index of `aKey` in sorted key order, or -1 if rejected.
+// - `bool`: `true` if `aKey` was inserted, or `false` otherwise.
+func (sm *TSortedMap[K, V]) InsertAt(aKey K, aValue V) (int, bool) {
+	if sm.safe {
+		sm.mtx.Lock()
+		defer sm.mtx.Unlock()
+	}
+
+	if !sm.insert(aKey, aValue) {
+		return -1, false
+	}
+	idx, _ := slices.BinarySearch(sm.keys, aKey)
+
+	return idx, true
+} // InsertAt()
+
+// `InsertErr()` adds or updates a key/value pair like `Insert()` but
+// reports why an entry wasn't inserted.
+//
+// Parameters:
+// - `aKey`: The key of the entry to be added or updated.
+// - `aValue`: The value to be associated with the key.
+//
+// Returns:
+// - `error`: `ErrInvalidValue` for a `NaN` key, or `nil` otherwise.
+func (sm *TSortedMap[K, V]) InsertErr(aKey K, aValue V) error {
+	if sm.safe {
+		sm.mtx.Lock()
+		defer sm.mtx.Unlock()
+	}
+
+	if !sm.insert(aKey, aValue) {
+		return ErrInvalidValue
+	}
+
+	return nil
+} // InsertErr()
+
+// `IsSafe()` returns whether the current map is thread-safe.
+//
+// A `TSortedMap` instance is thread-safe if it was created with the `aSafe`
+// flag set to `true` when calling the constructor function `NewMap()`.
+// In a thread-safe instance, all methods can be called concurrently
+// without causing data corruption or race conditions.
+//
+// Returns:
+//   - bool: A boolean value indicating whether the current map is thread-safe.
+func (sm *TSortedMap[K, V]) IsSafe() bool {
+	return sm.safe
+} // IsSafe()
+
+// `Iterate()` allows iteration over the map in sorted key order.
+//
+// Parameters:
+//   - `aFunc`: A function that takes a key and its associated value as arguments and performs some operation on them.
+//
+// Returns:
+//   - `*TSortedMap[K, V]`: A pointer to the same SortedMap instance,
+//
+// allowing method chaining.
+func (sm *TSortedMap[K, V]) Iterate(aFunc func(K, V)) *TSortedMap[K, V] {
+	if sm.safe {
+		sm.mtx.RLock()
+		defer sm.mtx.RUnlock()
+	}
+
+	for _, key := range sm.keys {
+		aFunc(key, sm.data[key])
+	}
+
+	return sm
+} // Iterate()
+
+// `IterateReverse()` allows iteration over the map in reverse sorted
+// key order, i.e. from the largest key down to the smallest.
+//
+// NOTE: For a thread-safe map the read lock is held during the whole
+// iteration, so `aFunc` must not call any other methods of this map.
+// Otherwise a deadlock might occur.
+//
+// Parameters:
+//   - `aFunc`: A function that takes a key and its associated value as arguments and performs some operation on them.
+//
+// Returns:
+//   - `*TSortedMap[K, V]`: A pointer to the same SortedMap instance,
+//
+// allowing method chaining.
+func (sm *TSortedMap[K, V]) IterateReverse(aFunc func(K, V)) *TSortedMap[K, V] {
+	if sm.safe {
+		sm.mtx.RLock()
+		defer sm.mtx.RUnlock()
+	}
+
+	for idx := len(sm.keys) - 1; 0 <= idx; idx-- {
+		key := sm.keys[idx]
+		aFunc(key, sm.data[key])
+	}
+
+	return sm
+} // IterateReverse()
+
+func (sm *TSortedMap[K, V]) Iterator() func() (K, V, bool) {
+	var idx int
+
+	return func() (K, V, bool) {
+		var (
+			key K
+			val V
+		)
+		if idx < len(sm.keys) {
+			key = sm.keys[idx]
+			val = sm.data[key]
+			idx++ // used from outer closure
+
+			return key, val, true
+		}
+
+		return key, val, false
+	}
+} // Iterator()
+
+// `MarshalJSON()` implements the `json.Marshaler` interface.
+//
+// The map is encoded as a JSON object whose members are written
+// strictly in the map's sorted key order (instead of relying on Go's
+// builtin map encoding). Non-string keys are formatted as JSON strings,
+// e.g. the key `42` becomes the member name `"42"`.
+//
+// Returns:
+// - `[]byte`: The JSON encoded map.
+// - `error`: A possible encoding error, or `nil`.
+func (sm *TSortedMap[K, V]) MarshalJSON() ([]byte, error) {
+	if sm.safe {
+		sm.mtx.RLock()
+		defer sm.mtx.RUnlock()
+	}
+
+	var buf bytes.Buffer
+	buf.WriteByte('{')
+	for idx, key := range sm.keys {
+		if 0 < idx {
+			buf.WriteByte(',')
+		}
+
+		name, err := marshalKey(key)
+		if nil != err {
+			return nil, err
+		}
+		buf.Write(name)
+		buf.WriteByte(':')
+
+		value, err := json.Marshal(sm.data[key])
+		if nil != err {
+			return nil, err
+		}
+		buf.Write(value)
+	}
+	buf.WriteByte('}')
+
+	return buf.Bytes(), nil
+} // MarshalJSON()
+
+// `MaxKey()` returns the entry with the largest key.
+//
+// Returns:
+// - `K`: The largest key.
+// - `V`: The value associated with the largest key.
+// - `bool`: An indication whether the map held any entries.
+func (sm *TSortedMap[K, V]) MaxKey() (K, V, bool) {
+	if sm.safe {
+		sm.mtx.RLock()
+		defer sm.mtx.RUnlock()
+	}
+	var (
+		key   K // variables with their zero value
+		value V
+	)
+
+	if kLen := len(sm.keys); 0 < kLen {
+		key = sm.keys[kLen-1]
+		return key, sm.data[key], true
+	}
+
+	return key, value, false
+} // MaxKey()
+
+// `MergeStrict()` adds all entries of `aOther` to the current map
+// unless both maps disagree on the value of a shared key.
+//
+// All shared keys are checked first, so in case of a conflict the
+// current map remains unmodified. Entries present in both maps with
+// equal values are left as they are.
+//
+// Parameters:
+// - `aOther`: The map whose entries are to be merged.
+//
+// Returns:
+// - `int`: The number of entries added.
+// - `error`: An `ErrConflict` naming the first conflicting key, or `nil`.
+func (sm *TSortedMap[K, V]) MergeStrict(aOther *TSortedMap[K, V]) (int, error) {
+	if (nil == aOther) || (sm == aOther) {
+		return 0, nil
+	}
+
+	// Lock both maps in a consistent order to avoid deadlocks.
+	if uintptr(unsafe.Pointer(aOther)) < uintptr(unsafe.Pointer(sm)) {
+		if aOther.safe {
+			aOther.mtx.RLock()
+			defer aOther.mtx.RUnlock()
+		}
+		if sm.safe {
+			sm.mtx.Lock()
+			defer sm.mtx.Unlock()
+		}
+	} else {
+		if sm.safe {
+			sm.mtx.Lock()
+			defer sm.mtx.Unlock()
+		}
+		if aOther.safe {
+			aOther.mtx.RLock()
+			defer aOther.mtx.RUnlock()
+		}
+	}
+
+	added := make([]K, 0, len(aOther.keys))
+	for _, key := range aOther.keys {
+		value, exists := sm.data[key]
+		if !exists {
+			added = append(added, key)
+			continue
+		}
+		if other := aOther.data[key]; value != other {
+			return 0, fmt.Errorf("%w for key '%v': '%v' vs. '%v'",
+				ErrConflict, key, value, other)
+		}
+	}
+	if 0 == len(added) {
+		return 0, nil
+	}
+
+	for _, key := range added {
+		value := aOther.data[key]
+		sm.data[key] = value
+		sm.indexAdd(key, value)
+		sm.stamp(key)
+		sm.lru.touch(key)
+	}
+	sm.keys = mergeSorted(sm.keys, added) // rebuild the key list just once
+
+	return len(added), nil
+} // MergeStrict()
+
+// `MinKey()` returns the entry with the smallest key.
+//
+// Returns:
+// - `K`: The smallest key.
+// - `V`: The value associated with the smallest key.
+// - `bool`: An indication whether the map held any entries.
+func (sm *TSortedMap[K, V]) MinKey() (K, V, bool) {
+	if sm.safe {
+		sm.mtx.RLock()
+		defer sm.mtx.RUnlock()
+	}
+	var (
+		key   K // variables with their zero value
+		value V
+	)
+
+	if 0 < len(sm.keys) {
+		key = sm.keys[0]
+		return key, sm.data[key], true
+	}
+
+	return key, value, false
+} // MinKey()
+
+func (sm *TSortedMap[K, V]) moveTo(aDest *TSortedMap[K, V], aFunc func(K, V) bool) int {
+	var (
+		result int
+		moved  []K
+	)
+	kept := make([]K, 0, len(sm.keys))
+
+	for _, key := range sm.keys {
+		value := sm.data[key]
+		if !aFunc(key, value) {
+			kept = append(kept, key)
+			continue
+		}
+
+		delete(sm.data, key)
+		delete(sm.stamps, key)
+		sm.lru.remove(key)
+		if _, exists := aDest.data[key]; !exists {
+			moved = append(moved, key)
+		}
+		aDest.data[key] = value
+		aDest.stamp(key)
+		aDest.lru.touch(key)
+		result++
+	}
+
+	if 0 < result {
+		// Rebuild both key lists (and reverse indices) just once
+		sm.keys = kept
+		aDest.keys = mergeSorted(aDest.keys, moved)
+		sm.reindex()
+		aDest.reindex()
+	}
+
+	return result
+} // moveTo()
+
+// `MoveTo()` transfers all entries matching `aFunc` from the current map
+// to `aDest`.
+//
+// Entries already present in `aDest` are overwritten by the moved ones.
+//
+// The write locks of both maps are acquired in a consistent order
+// (determined by their memory address) so that concurrent calls in
+// opposite directions can't cause a deadlock.
+//
+// Parameters:
+// - `aDest`: The map to receive the matching entries.
+// - `aFunc`: The function deciding whether an entry should be moved.
+//
+// Returns:
+// - `int`: The number of entries moved.
+func (sm *TSortedMap[K, V]) MoveTo(aDest *TSortedMap[K, V], aFunc func(K, V) bool) int {
+	if (nil == aDest) || (sm == aDest) {
+		return 0
+	}
+
+	first, second := sm, aDest
+	if uintptr(unsafe.Pointer(aDest)) < uintptr(unsafe.Pointer(sm)) {
+		first, second = aDest, sm
+	}
+	if first.safe {
+		first.mtx.Lock()
+		defer first.mtx.Unlock()
+	}
+	if second.safe {
+		second.mtx.Lock()
+		defer second.mtx.Unlock()
+	}
+
+	return sm.moveTo(aDest, aFunc)
+} // MoveTo()
+
+// `PopRange()` removes all entries with keys within the closed interval
+// `[aLow, aHigh]` and returns them.
+//
+// Collecting and removing the entries is done while holding the write
+// lock, so concurrent readers never see a partially removed range.
+//
+// Parameters:
+// - `aLow`: The lower bound of the key interval.
+// - `aHigh`: The upper bound of the key interval.
+//
+// Returns:
+// - `[]TMapEntry[K, V]`: The removed entries in sorted key order.
+func (sm *TSortedMap[K, V]) PopRange(aLow, aHigh K) []TMapEntry[K, V] {
+	if emptyRange(aLow, aHigh) {
+		return []TMapEntry[K, V]{}
+	}
+	if sm.safe {
+		sm.mtx.Lock()
+		defer sm.mtx.Unlock()
+	}
+
+	start, _ := slices.BinarySearch(sm.keys, aLow)
+	end, found := slices.BinarySearch(sm.keys, aHigh)
+	if found {
+		end++
+	}
+
+	result := make([]TMapEntry[K, V], 0, end-start)
+	for _, key := range sm.keys[start:end] {
+		value := sm.data[key]
+		result = append(result, TMapEntry[K, V]{Key: key, Value: value})
+		delete(sm.data, key)
+		delete(sm.stamps, key)
+		sm.lru.remove(key)
+		sm.indexRemove(key, value)
+	}
+	if 0 < len(result) {
+		sm.keys = slices.Delete(sm.keys, start, end)
+	}
+
+	return result
+} // PopRange()
+
+// `Purge()` removes all entries inserted more than `aOlderThan` ago.
+//
+// Only maps created by `NewExpiringMap()` record the insertion times
+// of their entries; for all other maps this method does nothing.
+//
+// Parameters:
+// - `aOlderThan`: The maximum age of the entries to keep.
+//
+// Returns:
+// - `int`: The number of entries removed.
+func (sm *TSortedMap[K, V]) Purge(aOlderThan time.Duration) int {
+	if sm.safe {
+		sm.mtx.Lock()
+		defer sm.mtx.Unlock()
+	}
+	if nil == sm.stamps {
+		return 0
+	}
+
+	var result int
+	cutoff := time.Now().Add(-aOlderThan)
+	for key, stamp := range sm.stamps {
+		if stamp.Before(cutoff) {
+			sm.indexRemove(key, sm.data[key])
+			delete(sm.data, key)
+			delete(sm.stamps, key)
+			sm.lru.remove(key)
+			result++
+		}
+	}
+
+	if 0 < result {
+		// Update the keys slice in a single pass
+		sm.keys = slices.DeleteFunc(sm.keys, func(aKey K) bool {
+			_, exists := sm.data[aKey]
+			return !exists
+		})
+	}
+
+	return result
+} // Purge()
+
+// `RangeMap()` returns all entries with keys within the closed interval
+// `[aLow, aHigh]` as a plain Go map.
+//
+// Parameters:
+// - `aLow`: The lower bound of the key interval.
+// - `aHigh`: The upper bound of the key interval.
+//
+// Returns:
+// - `map[K]V`: A copy of the entries within the interval.
+func (sm *TSortedMap[K, V]) RangeMap(aLow, aHigh K) map[K]V {
+	if emptyRange(aLow, aHigh) {
+		return map[K]V{}
+	}
+	if sm.safe {
+		sm.mtx.RLock()
+		defer sm.mtx.RUnlock()
+	}
+
+	start, _ := slices.BinarySearch(sm.keys, aLow)
+	end, found := slices.BinarySearch(sm.keys, aHigh)
+	if found {
+		end++
+	}
+
+	result := make(map[K]V, end-start)
+	for _, key := range sm.keys[start:end] {
+		result[key] = sm.data[key]
+	}
+
+	return result
+} // RangeMap()
+
+// `ReadJSON()` replaces the map's entries by the JSON object read
+// from `aReader`.
+//
+// Other than `UnmarshalJSON()` the object is decoded member by member,
+// so the whole document never has to be buffered. Member names of
+// non-string key types are expected to hold the key's string
+// representation as written by `WriteJSON()` or `MarshalJSON()`.
+// The write lock is held while loading; in case of an error the map
+// remains unchanged.
+//
+// Parameters:
+// - `aReader`: The source of the JSON encoded map.
+//
+// Returns:
+// - `error`: A possible read or decoding error, or `nil`.
+func (sm *TSortedMap[K, V]) ReadJSON(aReader io.Reader) error {
+	if sm.safe {
+		sm.mtx.Lock()
+		defer sm.mtx.Unlock()
+	}
+
+	dec := json.NewDecoder(aReader)
+	if tok, err := dec.Token(); nil != err {
+		return err
+	} else if json.Delim('{') != tok {
+		return fmt.Errorf("expected JSON object, got %v", tok)
+	}
+
+	data := make(map[K]V)
+	keys := make([]K, 0, 32)
+	for dec.More() {
+		tok, err := dec.Token()
+		if nil != err {
+			return err
+		}
+		name, _ := tok.(string) // member names are always strings
+		key, err := unmarshalKey[K](name)
+		if nil != err {
+			return err
+		}
+
+		var value V
+		if err = dec.Decode(&value); nil != err {
+			return err
+		}
+		if isNaN(key) {
+			continue
+		}
+		if _, exists := data[key]; !exists {
+			keys = append(keys, key)
+		}
+		data[key] = value
+	}
+	if _, err := dec.Token(); nil != err { // closing brace
+		return err
+	}
+	slices.Sort(keys) // ascending
+
+	sm.data = data
+	sm.keys = keys
+	sm.reindex()
+	if nil != sm.stamps {
+		sm.stamps = make(map[K]time.Time, len(keys))
+	}
+	sm.lru.reset()
+	for _, key := range keys {
+		sm.stamp(key)
+		sm.lru.touch(key)
+	}
+
+	return nil
+} // ReadJSON()
+
+// `RebuildIndex()` discards the current list of keys and rebuilds it
+// from the actual map entries.
+//
+// This is a recovery operation to restore the map's consistency (see
+// `Validate()`) without losing any data.
+//
+// Returns:
+// - `*TSortedMap[K, V]`: The repaired map.
+func (sm *TSortedMap[K, V]) RebuildIndex() *TSortedMap[K, V] {
+	if sm.safe {
+		sm.mtx.Lock()
+		defer sm.mtx.Unlock()
+	}
+
+	keys := make([]K, 0, len(sm.data))
+	for key := range sm.data {
+		keys = append(keys, key)
+	}
+	slices.Sort(keys) // ascending
+	sm.keys = keys
+	sm.reindex()
+
+	return sm
+} // RebuildIndex()
+
+// `reindex()` rebuilds the reverse index (if enabled) from the sorted
+// list of keys.
+func (sm *TSortedMap[K, V]) reindex() {
+	if nil == sm.index {
+		return
+	}
+
+	sm.index = make(map[V][]K)
+	for _, key := range sm.keys {
+		value := sm.data[key]
+		sm.index[value] = append(sm.index[value], key)
+	}
+} // reindex()
+
+// `RemoveIf()` deletes all entries for which `aFunc` returns `true`
+// and returns them.
+//
+// Collecting and removing the entries is done in a single pass while
+// holding the write lock, so `aFunc` must not call any methods of the
+// current map.
+//
+// Parameters:
+// - `aFunc`: The function deciding whether an entry should be removed.
+//
+// Returns:
+// - `[]TMapEntry[K, V]`: The removed entries in sorted key order.
+func (sm *TSortedMap[K, V]) RemoveIf(aFunc func(K, V) bool) []TMapEntry[K, V] {
+	result := []TMapEntry[K, V]{}
+	if nil == aFunc {
+		return result
+	}
+	if sm.safe {
+		sm.mtx.Lock()
+		defer sm.mtx.Unlock()
+	}
+
+	sm.keys = slices.DeleteFunc(sm.keys, func(aKey K) bool {
+		value := sm.data[aKey]
+		if !aFunc(aKey, value) {
+			return false
+		}
+		result = append(result, TMapEntry[K, V]{Key: aKey, Value: value})
+		delete(sm.data, aKey)
+		delete(sm.stamps, aKey)
+		sm.lru.remove(aKey)
+		sm.indexRemove(aKey, value)
+		return true
+	})
+
+	return result
+} // RemoveIf()
+
+// `RemoveValue()` deletes all entries holding `aValue`.
+//
+// Parameters:
+// - `aValue`: The value of the entries to be deleted.
+//
+// Returns:
+// - `int`: The number of entries removed.
+func (sm *TSortedMap[K, V]) RemoveValue(aValue V) int {
+	if sm.safe {
+		sm.mtx.Lock()
+		defer sm.mtx.Unlock()
+	}
+
+	keys := sm.findIndex(aValue)
+	if 0 == len(keys) {
+		return 0
+	}
+	for _, key := range keys {
+		delete(sm.data, key)
+		delete(sm.stamps, key)
+		sm.lru.remove(key)
+	}
+	if nil != sm.index {
+		delete(sm.index, aValue)
+	}
+
+	// Update the keys slice in a single pass
+	sm.keys = slices.DeleteFunc(sm.keys, func(aKey K) bool {
+		_, exists := sm.data[aKey]
+		return !exists
+	})
+
+	return len(keys)
+} // RemoveValue()
+
+func (sm *TSortedMap[K, V]) rename(aOldKey, aNewKey K) bool {
+	if isNaN(aNewKey) { // NaN would corrupt the key ordering
+		return false
+	}
+
+	// Check if the new key already exists
+	if _, exists := sm.data[aNewKey]; exists {
+		return false
+	}
+
+	// Check if the old key exists
+	oldValue, exists := sm.data[aOldKey]
+	if !exists || (aOldKey == aNewKey) {
+		return false
+	}
+
+	// Remove the old key and add the new key
+	delete(sm.data, aOldKey)
+	sm.data[aNewKey] = oldValue
+	if stamp, ok := sm.stamps[aOldKey]; ok {
+		delete(sm.stamps, aOldKey)
+		sm.stamps[aNewKey] = stamp
+	}
+	sm.lru.rename(aOldKey, aNewKey)
+	sm.indexRemove(aOldKey, oldValue)
+	sm.indexAdd(aNewKey, oldValue)
+
+	// Update the keys slice: only a single key moves, so instead of
+	// re-sorting remove the old key and insert the new one in place.
+	if idx, found := slices.BinarySearch(sm.keys, aOldKey); found {
+		sm.keys = slices.Delete(sm.keys, idx, idx+1)
+	}
+	idx, _ := slices.BinarySearch(sm.keys, aNewKey)
+	sm.keys = slices.Insert(sm.keys, idx, aNewKey)
+
+	return true
+} // rename()
+
+// `Rename()` changes the key of an existing entry without affecting its value.
+//
+// If `aOldKey` equals `aNewKey`, or aOldKey` doesn't exist then they are
+// silently ignored (i.e. this method does nothing), returning `false`.
+//
+// If `aNewKey` already exists, it is ignored and the method returns `false`.
+//
+// Parameters:
+// - `aOldKey`: the key to be replaced in this list.
+// - `aNewKey`: The replacement key in this list.
+//
+// Returns:
+// - `bool`: `true` if the the renaming was successful, or `false` otherwise.
+func (sm *TSortedMap[K, V]) Rename(aOldKey, aNewKey K) bool {
+	if sm.safe {
+		sm.mtx.Lock()
+		defer sm.mtx.Unlock()
+	}
+
+	return sm.rename(aOldKey, aNewKey)
+} // Rename()
+
+// `ReplaceValue()` changes the value of all entries holding `aOld`
+// to `aNew`.
+//
+// The keys and their order are not affected. Like any other update the
+// changed entries get a new insertion time (see `NewExpiringMap()`) and
+// are marked as recently used in ascending key order (see `NewLRUMap()`).
+//
+// Parameters:
+// - `aOld`: The value to be replaced.
+// - `aNew`: The replacement value.
+//
+// Returns:
+// - `int`: The number of entries changed.
+func (sm *TSortedMap[K, V]) ReplaceValue(aOld, aNew V) int {
+	if sm.safe {
+		sm.mtx.Lock()
+		defer sm.mtx.Unlock()
+	}
+	if aOld == aNew {
+		return 0
+	}
+
+	var result int
+	for _, key := range sm.keys {
+		if sm.data[key] == aOld {
+			sm.data[key] = aNew
+			sm.stamp(key)
+			sm.lru.touch(key)
+			result++
+		}
+	}
+	if 0 < result {
+		sm.reindex()
+	}
+
+	return result
+} // ReplaceValue()
+
+// `Scan()` implements the `sql.Scanner` interface.
+//
+// The database value is expected to be a JSON object as written by
+// `Value()`. The map's current entries get replaced by the scanned
+// ones. A `nil` value results in an empty map.
+//
+// Parameters:
+// - `aSrc`: The database value (`[]byte`, `string`, or `nil`).
+//
+// Returns:
+// - `error`: A possible decoding error, or `nil`.
+func (sm *TSortedMap[K, V]) Scan(aSrc any) error {
+	switch src := aSrc.(type) {
+	case nil:
+		sm.Clear()
+		return nil
+	case []byte:
+		return sm.UnmarshalJSON(src)
+	case string:
+		return sm.UnmarshalJSON([]byte(src))
+	default:
+		return fmt.Errorf("can't scan type %T into TSortedMap", aSrc)
+	}
+} // Scan()
+
+// `Reset()` empties the map and sets its thread-safety, reusing the
+// current instance.
+//
+// This allows e.g. keeping instances in a `sync.Pool` without
+// allocating a new map (and its mutex) for each use; like `Clear()` the
+// capacity of the internal data structures is kept. A reverse index
+// (see `NewIndexedMap()`) stays enabled.
+//
+// NOTE: Changing the thread-safety setting is only safe while no other
+// goroutine is using the map, e.g. right after taking it from a pool.
+//
+// Parameters:
+// - `aSafe`: Flag to decide whether the map should be thread safe.
+//
+// Returns:
+// - `*TSortedMap[K, V]`: The reset map instance.
+func (sm *TSortedMap[K, V]) Reset(aSafe bool) *TSortedMap[K, V] {
+	if sm.safe {
+		sm.mtx.Lock()
+		defer sm.mtx.Unlock()
+	}
+
+	sm.clear()
+	sm.safe = aSafe
+
+	return sm
+} // Reset()
+
+// `SearchKey()` looks up `aKey` in the sorted list of keys using a
+// binary search.
+//
+// Like `slices.BinarySearch()` it returns the position where `aKey`
+// is found or would be inserted, allowing callers to implement their
+// own range or neighbour logic.
+//
+// Parameters:
+// - `aKey`: The key to look up.
+//
+// Returns:
+// - `int`: The (insertion) index of `aKey` in the sorted keys.
+// - `bool`: An indication whether `aKey` is present in the map.
+func (sm *TSortedMap[K, V]) SearchKey(aKey K) (int, bool) {
+	if sm.safe {
+		sm.mtx.RLock()
+		defer sm.mtx.RUnlock()
+	}
+
+	return slices.BinarySearch(sm.keys, aKey)
+} // SearchKey()
+
+// `SetValueAt()` changes the value of the entry at position `aIndex`
+// in sorted key order.
+//
+// The keys are not affected, so no re-sorting is required. As with
+// `Insert()` the entry's insertion time is renewed and it becomes the
+// most recently used one.
+//
+// Parameters:
+// - `aIndex`: The position of the entry in sorted key order.
+// - `aNewValue`: The new value of the entry.
+//
+// Returns:
+// - `bool`: `true` if the value was set, or `false` if `aIndex` is out of range.
+func (sm *TSortedMap[K, V]) SetValueAt(aIndex int, aNewValue V) bool {
+	if sm.safe {
+		sm.mtx.Lock()
+		defer sm.mtx.Unlock()
 	}
 
-	// Check if the keys are in the same order
-	if !slices.Equal(sm.keys, aMap.keys) {
+	if (0 > aIndex) || (aIndex >= len(sm.keys)) {
 		return false
 	}
-
-	// Check if the values are equal for each key
-	for key, val1 := range sm.data {
-		if val2, ok := aMap.data[key]; !ok || val1 != val2 {
-			return false
-		}
-	}
+	key := sm.keys[aIndex]
+	sm.indexRemove(key, sm.data[key])
+	sm.data[key] = aNewValue
+	sm.indexAdd(key, aNewValue)
+	sm.stamp(key)
+	sm.lru.touch(key)
 
 	return true
-} // equals()
+} // SetValueAt()
 
-func (sm *TSortedMap[K, V]) Equals(aMap *TSortedMap[K, V]) bool {
+// `Snapshot()` returns a read-only view of the map's current entries.
+//
+// The view holds a frozen copy of the entries, so later modifications
+// of the map don't affect it, and it provides non-modifying methods
+// only. The keys are already sorted, so no sorting is done.
+//
+// Returns:
+// - `TReadOnlySortedMap[K, V]`: The immutable view of the map.
+func (sm *TSortedMap[K, V]) Snapshot() TReadOnlySortedMap[K, V] {
 	if sm.safe {
 		sm.mtx.RLock()
 		defer sm.mtx.RUnlock()
 	}
-	if aMap.safe {
-		aMap.mtx.RLock()
-		defer aMap.mtx.RUnlock()
-	}
 
-	return sm.equals(aMap)
-} // Equals()
-
-func (sm *TSortedMap[K, V]) findIndex(aValue V) []K {
-	var result []K
-
-	for _, key := range sm.keys {
-		if sm.data[key] == aValue {
-			result = append(result, key)
-		}
+	result := TReadOnlySortedMap[K, V]{
+		data: make(map[K]V, len(sm.data)),
+		keys: append(make([]K, 0, len(sm.keys)), sm.keys...),
+	}
+	for key, value := range sm.data {
+		result.data[key] = value
 	}
 
 	return result
-} // findIndex()
+} // Snapshot()
 
-// `FindIndex()` returns a slice of keys that have the given value.
+// `SplitAt()` divides the map into two new maps at `aKey`.
+//
+// The first returned map holds all entries with keys less than `aKey`,
+// the second one all entries with keys greater than or equal to `aKey`.
+// Both new maps use the same thread-safety setting as the current one.
+// The current map is not modified.
 //
 // Parameters:
-// - `aValue`: The element to look up.
+// - `aKey`: The key at which to split the map.
 //
 // Returns:
-// - `[]K`: The index of `aID` in the list.
-func (sm *TSortedMap[K, V]) FindIndex(aValue V) []K {
+// - `*TSortedMap[K, V]`: A map with the keys `< aKey`.
+// - `*TSortedMap[K, V]`: A map with the keys `>= aKey`.
+func (sm *TSortedMap[K, V]) SplitAt(aKey K) (rLower, rUpper *TSortedMap[K, V]) {
 	if sm.safe {
 		sm.mtx.RLock()
 		defer sm.mtx.RUnlock()
 	}
 
-	return sm.findIndex(aValue)
-} // FindIndex()
+	idx, _ := slices.BinarySearch(sm.keys, aKey)
 
-// `Get()` retrieves a value by its key from the SortedMap
+	// Both key lists are already sorted, so there's no need to sort again.
+	split := func(aKeys []K) *TSortedMap[K, V] {
+		result := &TSortedMap[K, V]{
+			data: make(map[K]V, len(aKeys)),
+			keys: append(make([]K, 0, len(aKeys)), aKeys...),
+			safe: sm.safe,
+		}
+		for _, key := range aKeys {
+			result.data[key] = sm.data[key]
+		}
+		if nil != sm.index {
+			result.index = make(map[V][]K)
+			result.reindex()
+		}
+		if nil != sm.stamps {
+			result.stamps = make(map[K]time.Time, len(aKeys))
+			for _, key := range aKeys {
+				result.stamps[key] = sm.stamps[key]
+			}
+		}
+		result.lru = sm.lru.clone(func(aKey K) bool {
+			_, exists := result.data[aKey]
+			return exists
+		})
+
+		return result
+	}
+
+	return split(sm.keys[:idx]), split(sm.keys[idx:])
+} // SplitAt()
+
+// `stamp()` records the current time as insertion time of `aKey`
+// (see `NewExpiringMap()`).
 //
 // Parameters:
-// - `aKey`: The key of the entry to be retrieved.
-//
-// Returns:
-// - `V`: The value associated with the `aKey`.
-// - `bool`: An indication whether the key was found in the map.
-func (sm *TSortedMap[K, V]) Get(aKey K) (V, bool) {
+// - `aKey`: The key of the entry inserted.
+func (sm *TSortedMap[K, V]) stamp(aKey K) {
+	if nil != sm.stamps {
+		sm.stamps[aKey] = time.Now()
+	}
+} // stamp()
+
+func (sm *TSortedMap[K, V]) string() (rStr string) {
+	// Access items in sorted order:
+	iter := sm.Iterator()
+	for key, value, hasNext := iter(); hasNext; key, value, hasNext = iter() {
+		rStr += fmt.Sprintf("[%v]\n%v\n", key, value)
+	}
+	return
+} // string()
+
+func (sm *TSortedMap[K, V]) String() string {
 	if sm.safe {
 		sm.mtx.RLock()
 		defer sm.mtx.RUnlock()
 	}
 
-	value, exists := sm.data[aKey]
-
-	return value, exists
-} // Get()
-
-// Keys returns a slice of all keys in sorted order
+	return sm.string()
+} // String()
 
-// `Keys()` returns a slice of all keys in sorted order
+// `StringTable()` renders the map as two aligned columns.
 //
-// This method returns a slice of all keys in the map in sorted order.
+// Each entry is written on a line of its own in sorted key order; the
+// keys are padded to the width of the longest key, followed by two
+// spaces and the entry's value.
 //
 // Returns:
-// - `[]K`: A slice of keys in the sorted map.
-func (sm *TSortedMap[K, V]) Keys() []K {
+// - `string`: The map's contents as a table.
+func (sm *TSortedMap[K, V]) StringTable() string {
 	if sm.safe {
 		sm.mtx.RLock()
 		defer sm.mtx.RUnlock()
 	}
 
-	return append([]K{}, sm.keys...)
-} // Keys()
-
-func (sm *TSortedMap[K, V]) insert(aKey K, aValue V) bool {
-	if _, exists := sm.data[aKey]; exists {
-		sm.data[aKey] = aValue
-
-		return true
+	names := make([]string, len(sm.keys))
+	width := 0
+	for idx, key := range sm.keys {
+		names[idx] = fmt.Sprintf("%v", key)
+		width = max(width, utf8.RuneCountInString(names[idx]))
 	}
 
-	// There are different situations to consider:
-	// 1: the key-list is empty,
-	// 2: the key-list doesn't already contain the key,
-	// 3: the key-list contains the key but with a different value
-	sLen := len(sm.keys)
-	if 0 == sLen {
-		// 1: empty list: just add the new item
-		sm.keys = append(sm.keys, aKey)
-	} else {
-		// find the insertion index using binary search
-		idx := sort.Search(sLen, func(i int) bool {
-			return sm.keys[i] >= aKey
-		})
-
-		if sLen == idx {
-			// 2: key not found: add key at the end
-			sm.keys = append(sm.keys, aKey)
-		} else if (sm.keys)[idx] != aKey {
-			// 3: the search index doesn't point to the required key
-			sm.keys = append(sm.keys, aKey)
-			copy((sm.keys)[idx+1:], (sm.keys)[idx:])
-			(sm.keys)[idx] = aKey
-		} else {
-			// dummy instruction for debugger
-			sLen = 0
-		}
+	var builder strings.Builder
+	for idx, key := range sm.keys {
+		builder.WriteString(names[idx])
+		builder.WriteString(strings.Repeat(" ", width-utf8.RuneCountInString(names[idx])+2))
+		builder.WriteString(fmt.Sprintf("%v\n", sm.data[key]))
 	}
-	sm.data[aKey] = aValue
 
-	return true
-} // Insert()
+	return builder.String()
+} // StringTable()
 
-// `Insert()` adds or updates a key/value pair in the sorted map.
+// `TrimAboveKey()` removes all entries with keys greater than `aKey`.
 //
 // Parameters:
-// - `aKey`: The key of the entry to be added or updated.
-// - `aValue`: The value to be associated with the key.
+// - `aKey`: The largest key to keep.
 //
 // Returns:
-// - `bool`: `true` if `aID` was inserted, or `false` otherwise.
-func (sm *TSortedMap[K, V]) Insert(aKey K, aValue V) bool {
+// - `int`: The number of entries removed.
+func (sm *TSortedMap[K, V]) TrimAboveKey(aKey K) int {
+	if isNaN(aKey) {
+		return 0
+	}
 	if sm.safe {
 		sm.mtx.Lock()
 		defer sm.mtx.Unlock()
 	}
 
-	return sm.insert(aKey, aValue)
-} // Insert()
+	idx, found := slices.BinarySearch(sm.keys, aKey)
+	if found {
+		idx++
+	}
 
-// `IsSafe()` returns whether the current map is thread-safe.
+	return sm.trimKeys(idx, len(sm.keys))
+} // TrimAboveKey()
+
+// `TrimBelowKey()` removes all entries with keys less than `aKey`.
 //
-// A `TSortedMap` instance is thread-safe if it was created with the `aSafe`
-// flag set to `true` when calling the constructor function `NewMap()`.
-// In a thread-safe instance, all methods can be called concurrently
-// without causing data corruption or race conditions.
+// Parameters:
+// - `aKey`: The smallest key to keep.
 //
 // Returns:
-//   - bool: A boolean value indicating whether the current map is thread-safe.
-func (sm *TSortedMap[K, V]) IsSafe() bool {
-	return sm.safe
-} // IsSafe()
+// - `int`: The number of entries removed.
+func (sm *TSortedMap[K, V]) TrimBelowKey(aKey K) int {
+	if isNaN(aKey) {
+		return 0
+	}
+	if sm.safe {
+		sm.mtx.Lock()
+		defer sm.mtx.Unlock()
+	}
 
-// `Iterate()` allows iteration over the map in sorted key order.
+	idx, _ := slices.BinarySearch(sm.keys, aKey)
+
+	return sm.trimKeys(0, idx)
+} // TrimBelowKey()
+
+// `trimKeys()` removes the entries of the keys at the positions
+// `[aStart, aEnd)`.
 //
 // Parameters:
-//   - `aFunc`: A function that takes a key and its associated value as arguments and performs some operation on them.
+// - `aStart`: The position of the first key to remove.
+// - `aEnd`: The position after the last key to remove.
 //
 // Returns:
-//   - `*TSortedMap[K, V]`: A pointer to the same SortedMap instance,
-//
-// allowing method chaining.
-func (sm *TSortedMap[K, V]) Iterate(aFunc func(K, V)) *TSortedMap[K, V] {
-	if sm.safe {
-		sm.mtx.RLock()
-		defer sm.mtx.RUnlock()
+// - `int`: The number of entries removed.
+func (sm *TSortedMap[K, V]) trimKeys(aStart, aEnd int) int {
+	if aStart >= aEnd {
+		return 0
 	}
 
-	for _, key := range sm.keys {
-		aFunc(key, sm.data[key])
+	for _, key := range sm.keys[aStart:aEnd] {
+		sm.indexRemove(key, sm.data[key])
+		delete(sm.data, key)
+		delete(sm.stamps, key)
+		sm.lru.remove(key)
 	}
+	sm.keys = slices.Delete(sm.keys, aStart, aEnd)
 
-	return sm
-} // Iterate()
-
-func (sm *TSortedMap[K, V]) Iterator() func() (K, V, bool) {
-	var idx int
+	return aEnd - aStart
+} // trimKeys()
 
-	return func() (K, V, bool) {
-		var (
-			key K
-			val V
-		)
-		if idx < len(sm.keys) {
-			key = sm.keys[idx]
-			val = sm.data[key]
-			idx++ // used from outer closure
+// `UnmarshalJSON()` implements the `json.Unmarshaler` interface.
+//
+// The JSON object's members replace the map's current entries.
+// Member names of non-string key types are expected to hold the key's
+// string representation as written by `MarshalJSON()`.
+//
+// Parameters:
+// - `aData`: The JSON encoded map.
+//
+// Returns:
+// - `error`: A possible decoding error, or `nil`.
+func (sm *TSortedMap[K, V]) UnmarshalJSON(aData []byte) error {
+	var members map[string]json.RawMessage
+	if err := json.Unmarshal(aData, &members); nil != err {
+		return err
+	}
 
-			return key, val, true
+	data := make(map[K]V, len(members))
+	keys := make([]K, 0, len(members))
+	for name, raw := range members {
+		var value V
+		key, err := unmarshalKey[K](name)
+		if nil != err {
+			return err
 		}
-
-		return key, val, false
+		if isNaN(key) {
+			continue
+		}
+		if err := json.Unmarshal(raw, &value); nil != err {
+			return err
+		}
+		if _, exists := data[key]; !exists {
+			keys = append(keys, key)
+		}
+		data[key] = value
 	}
-} // Iterator()
+	slices.Sort(keys) // ascending
 
-func (sm *TSortedMap[K, V]) rename(aOldKey, aNewKey K) bool {
-	// Check if the new key already exists
-	if _, exists := sm.data[aNewKey]; exists {
-		return false
+	if sm.safe {
+		sm.mtx.Lock()
+		defer sm.mtx.Unlock()
 	}
-
-	// Check if the old key exists
-	oldValue, exists := sm.data[aOldKey]
-	if !exists || (aOldKey == aNewKey) {
-		return false
+	sm.data = data
+	sm.keys = keys
+	sm.reindex()
+	if nil != sm.stamps {
+		sm.stamps = make(map[K]time.Time, len(keys))
+	}
+	sm.lru.reset()
+	for _, key := range keys {
+		sm.stamp(key)
+		sm.lru.touch(key)
 	}
 
-	// Remove the old key and add the new key
-	delete(sm.data, aOldKey)
-	sm.data[aNewKey] = oldValue
+	return nil
+} // UnmarshalJSON()
+
+// `Validate()` checks the internal consistency of the map.
+//
+// The list of keys is expected to be sorted in ascending order without
+// any duplicates, and to contain exactly the keys of the map's entries.
+//
+// Returns:
+// - `error`: A description of the first inconsistency found, or `nil`.
+func (sm *TSortedMap[K, V]) Validate() error {
+	if sm.safe {
+		sm.mtx.RLock()
+		defer sm.mtx.RUnlock()
+	}
 
-	// Update the keys slice
 	for idx, key := range sm.keys {
-		if key == aOldKey {
-			sm.keys[idx] = aNewKey
-			break
+		if 0 < idx {
+			if prev := sm.keys[idx-1]; !(prev < key) {
+				if prev == key {
+					return fmt.Errorf("duplicate key '%v' at index %d", key, idx)
+				}
+				return fmt.Errorf("key '%v' at index %d is not less than key '%v' at index %d",
+					prev, idx-1, key, idx)
+			}
+		}
+		if _, exists := sm.data[key]; !exists {
+			return fmt.Errorf("key '%v' at index %d has no map entry", key, idx)
 		}
 	}
 
-	// Re-sort the keys
-	slices.Sort(sm.keys) // ascending
+	if len(sm.keys) != len(sm.data) {
+		return fmt.Errorf("%d keys listed but %d map entries present",
+			len(sm.keys), len(sm.data))
+	}
 
-	return true
-} // Rename()
+	return nil
+} // Validate()
 
-// `Rename()` changes the key of an existing entry without affecting its value.
+// `Value()` implements the `driver.Valuer` interface.
 //
-// If `aOldKey` equals `aNewKey`, or aOldKey` doesn't exist then they are
-// silently ignored (i.e. this method does nothing), returning `false`.
+// The map's entries are stored as a JSON object (see `MarshalJSON()`).
 //
-// If `aNewKey` already exists, it is ignored and the method returns `false`.
+// Returns:
+// - `driver.Value`: The JSON encoded map.
+// - `error`: A possible encoding error, or `nil`.
+func (sm *TSortedMap[K, V]) Value() (driver.Value, error) {
+	result, err := sm.MarshalJSON()
+	if nil != err {
+		return nil, err
+	}
+
+	return string(result), nil
+} // Value()
+
+// `WriteJSON()` writes the map as a JSON object to `aWriter`.
+//
+// Other than `MarshalJSON()` the entries are encoded one by one and
+// streamed directly to `aWriter` in sorted key order, so no buffer
+// holding the whole document is needed. The read lock is held until
+// all entries are written. Each value is followed by a newline (as
+// written by `json.Encoder`) which is valid JSON whitespace.
 //
 // Parameters:
-// - `aOldKey`: the key to be replaced in this list.
-// - `aNewKey`: The replacement key in this list.
+// - `aWriter`: The destination of the JSON encoded map.
 //
 // Returns:
-// - `bool`: `true` if the the renaming was successful, or `false` otherwise.
-func (sm *TSortedMap[K, V]) Rename(aOldKey, aNewKey K) bool {
+// - `error`: A possible encoding or write error, or `nil`.
+func (sm *TSortedMap[K, V]) WriteJSON(aWriter io.Writer) error {
 	if sm.safe {
-		sm.mtx.Lock()
-		defer sm.mtx.Unlock()
+		sm.mtx.RLock()
+		defer sm.mtx.RUnlock()
 	}
 
-	return sm.rename(aOldKey, aNewKey)
-} // Rename()
-
-func (sm *TSortedMap[K, V]) string() (rStr string) {
-	// Access items in sorted order:
-	iter := sm.Iterator()
-	for key, value, hasNext := iter(); hasNext; key, value, hasNext = iter() {
-		rStr += fmt.Sprintf("[%v]\n%v\n", key, value)
+	enc := json.NewEncoder(aWriter)
+	if _, err := io.WriteString(aWriter, "{"); nil != err {
+		return err
 	}
-	return
-} // string()
-
-func (sm *TSortedMap[K, V]) String() string {
-	if sm.safe {
-		sm.mtx.RLock()
-		defer sm.mtx.RUnlock()
+	for idx, key := range sm.keys {
+		name, err := marshalKey(key)
+		if nil != err {
+			return err
+		}
+		if 0 < idx {
+			name = append([]byte{','}, name...)
+		}
+		if _, err = aWriter.Write(append(name, ':')); nil != err {
+			return err
+		}
+		if err = enc.Encode(sm.data[key]); nil != err {
+			return err
+		}
 	}
+	_, err := io.WriteString(aWriter, "}")
 
-	return sm.string()
-} // String()
+	return err
+} // WriteJSON()
 
 /* EoF */
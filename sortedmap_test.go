@@ -0,0 +1,653 @@
+/*
+Copyright ©  2024  M.Watermann, 10247 Berlin, Germany
+
+		All rights reserved
+	EMail : <support@mwat.de>
+*/
+package sortedlists
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"math"
+	"slices"
+	"sync"
+	"testing"
+	"time"
+)
+
+//lint:file-ignore ST1017 - I prefer Yoda conditions
+
+func Test_TSortedMap_Insert_NaN(t *testing.T) {
+	sm := NewMap[float64, string](false)
+	sm.Insert(1, "one")
+	sm.Insert(2, "two")
+
+	if sm.Insert(math.NaN(), "nan") {
+		t.Error("Insert(NaN) = true, want false")
+	}
+	if got := len(sm.Keys()); 2 != got {
+		t.Errorf("len(Keys()) = %d, want 2", got)
+	}
+	if err := sm.Validate(); nil != err {
+		t.Errorf("Validate() = %v, want nil", err)
+	}
+} // Test_TSortedMap_Insert_NaN()
+
+func Test_TSortedMap_Clone_config(t *testing.T) {
+	sm := NewIndexedMap[int, string](true)
+	sm.Insert(1, "a")
+	sm.Insert(2, "b")
+
+	clone := sm.Clone()
+	if !clone.IsSafe() {
+		t.Error("IsSafe() = false, want true")
+	}
+	if nil == clone.index {
+		t.Fatal("reverse index not cloned")
+	}
+	clone.Insert(3, "a")
+	if got := clone.FindIndex("a"); 2 != len(got) || 1 != got[0] || 3 != got[1] {
+		t.Errorf("FindIndex(a) = %v, want [1 3]", got)
+	}
+	if got := sm.FindIndex("a"); 1 != len(got) {
+		t.Errorf("original FindIndex(a) = %v, want [1]", got)
+	}
+} // Test_TSortedMap_Clone_config()
+
+func Test_TSortedMap_MarshalJSON_keyOrder(t *testing.T) {
+	sm := NewMap[int, string](false)
+	for _, key := range []int{10, 2, 33, 1} {
+		sm.Insert(key, fmt.Sprint("v", key))
+	}
+
+	got, err := sm.MarshalJSON()
+	if nil != err {
+		t.Fatalf("MarshalJSON() error = %v", err)
+	}
+	want := `{"1":"v1","2":"v2","10":"v10","33":"v33"}`
+	if want != string(got) {
+		t.Errorf("MarshalJSON() = %s, want %s", got, want)
+	}
+
+	// The member order must match the order of `Keys()`.
+	dec := json.NewDecoder(bytes.NewReader(got))
+	_, _ = dec.Token() // opening brace
+	for idx, key := range sm.Keys() {
+		tok, err := dec.Token()
+		if nil != err {
+			t.Fatalf("Token() error = %v", err)
+		}
+		if name := fmt.Sprint(key); name != tok {
+			t.Errorf("member %d = %v, want %q", idx, tok, name)
+		}
+		var value string
+		if err = dec.Decode(&value); nil != err {
+			t.Fatalf("Decode() error = %v", err)
+		}
+	}
+} // Test_TSortedMap_MarshalJSON_keyOrder()
+
+// `benchMap()` returns a map with `aSize` entries for benchmarks.
+func benchMap(aSize int) *TSortedMap[int, int] {
+	sm := NewMap[int, int](false)
+	for i := range aSize {
+		sm.Insert(i, i)
+	}
+
+	return sm
+} // benchMap()
+
+// `naiveClone()` copies the map without preallocating its structures,
+// as `Clone()` did before.
+func naiveClone(aMap *TSortedMap[int, int]) *TSortedMap[int, int] {
+	result := &TSortedMap[int, int]{
+		data: make(map[int]int),
+		safe: aMap.safe,
+	}
+	for _, key := range aMap.keys {
+		result.data[key] = aMap.data[key]
+		result.keys = append(result.keys, key)
+	}
+
+	return result
+} // naiveClone()
+
+func Benchmark_TSortedMap_Clone(b *testing.B) {
+	sm := benchMap(100_000)
+
+	b.Run("preallocated", func(b *testing.B) {
+		b.ReportAllocs()
+		for range b.N {
+			_ = sm.Clone()
+		}
+	})
+	b.Run("naive", func(b *testing.B) {
+		b.ReportAllocs()
+		for range b.N {
+			_ = naiveClone(sm)
+		}
+	})
+} // Benchmark_TSortedMap_Clone()
+
+// `linearDelete()` removes `aKey` by scanning the list of keys,
+// as `delete()` did before.
+func linearDelete(aMap *TSortedMap[int, int], aKey int) bool {
+	if _, exists := aMap.data[aKey]; !exists {
+		return false
+	}
+	delete(aMap.data, aKey)
+	for idx, key := range aMap.keys {
+		if key == aKey {
+			aMap.keys = append(aMap.keys[:idx], aMap.keys[idx+1:]...)
+			break
+		}
+	}
+
+	return true
+} // linearDelete()
+
+func Benchmark_TSortedMap_Delete(b *testing.B) {
+	const size = 100_000
+	// Deleting (and re-adding) the last key keeps the cost of shifting
+	// the keys negligible, so the lookup of the key dominates.
+	last := size - 1
+
+	b.Run("binary search", func(b *testing.B) {
+		sm := benchMap(size)
+		b.ResetTimer()
+		for range b.N {
+			sm.delete(last)
+			sm.insert(last, last)
+		}
+	})
+	b.Run("linear scan", func(b *testing.B) {
+		sm := benchMap(size)
+		b.ResetTimer()
+		for range b.N {
+			linearDelete(sm, last)
+			sm.insert(last, last)
+		}
+	})
+} // Benchmark_TSortedMap_Delete()
+
+// `sortRename()` changes a key and re-sorts all keys, as `rename()`
+// did before.
+func sortRename(aMap *TSortedMap[int, int], aOldKey, aNewKey int) bool {
+	value, exists := aMap.data[aOldKey]
+	if !exists {
+		return false
+	}
+	delete(aMap.data, aOldKey)
+	aMap.data[aNewKey] = value
+	for idx, key := range aMap.keys {
+		if key == aOldKey {
+			aMap.keys[idx] = aNewKey
+			break
+		}
+	}
+	slices.Sort(aMap.keys) // ascending
+
+	return true
+} // sortRename()
+
+func Benchmark_TSortedMap_Rename(b *testing.B) {
+	const size = 10_000
+	newMap := func() *TSortedMap[int, int] {
+		sm := NewMap[int, int](false)
+		for i := range size {
+			sm.Insert(i*2, i) // even keys only
+		}
+		return sm
+	}
+
+	// Each iteration moves a key to its odd neighbour and back.
+	b.Run("binary insert", func(b *testing.B) {
+		sm := newMap()
+		b.ResetTimer()
+		for i := range b.N {
+			key := (i % size) * 2
+			sm.rename(key, key+1)
+			sm.rename(key+1, key)
+		}
+	})
+	b.Run("full sort", func(b *testing.B) {
+		sm := newMap()
+		b.ResetTimer()
+		for i := range b.N {
+			key := (i % size) * 2
+			sortRename(sm, key, key+1)
+			sortRename(sm, key+1, key)
+		}
+	})
+} // Benchmark_TSortedMap_Rename()
+
+func Test_TSortedMap_Keys_copy(t *testing.T) {
+	sm := NewMap[int, string](true)
+	for _, key := range []int{3, 1, 2} {
+		sm.Insert(key, "v")
+	}
+
+	keys := sm.Keys()
+	keys[0] = 99
+	if got := sm.Keys(); !slices.Equal([]int{1, 2, 3}, got) {
+		t.Errorf("Keys() = %v, want [1 2 3]", got)
+	}
+
+	sm.Insert(0, "v")
+	if got := sm.Keys(); !slices.Equal([]int{0, 1, 2, 3}, got) {
+		t.Errorf("Keys() = %v, want [0 1 2 3]", got)
+	}
+} // Test_TSortedMap_Keys_copy()
+
+func Benchmark_TSortedMap_Keys(b *testing.B) {
+	sm := benchMap(10_000)
+
+	b.ReportAllocs()
+	for range b.N {
+		_ = sm.Keys()
+	}
+} // Benchmark_TSortedMap_Keys()
+
+func Test_MapValues_config(t *testing.T) {
+	indexed := NewIndexedMap[int, int](false)
+	expiring := NewExpiringMap[int, int](false)
+	lru := NewLRUMap[int, int](false)
+	for _, sm := range []*TSortedMap[int, int]{indexed, expiring, lru} {
+		sm.Insert(1, 1)
+		sm.Insert(2, 2)
+		sm.Insert(3, 1)
+	}
+	lru.Get(1) // key 2 becomes the least recently used one
+	toString := func(aValue int) string { return fmt.Sprint(aValue) }
+
+	if got := MapValues(indexed, toString).FindIndex("1"); !slices.Equal([]int{1, 3}, got) {
+		t.Errorf("FindIndex(1) = %v, want [1 3]", got)
+	}
+	if got := MapValues(expiring, toString).Purge(-time.Hour); 3 != got {
+		t.Errorf("Purge(-1h) = %d, want 3", got)
+	}
+	if key, _, ok := MapValues(lru, toString).EvictLRU(); !ok || 2 != key {
+		t.Errorf("EvictLRU() = %d, %v, want 2, true", key, ok)
+	}
+} // Test_MapValues_config()
+
+func Test_TSortedMap_Clone_keysAliasing(t *testing.T) {
+	sm := NewMap[int, string](false)
+	for key := range 5 {
+		sm.Insert(key*2, "v")
+	}
+	sm.keys = slices.Grow(sm.keys, 10) // spare capacity to be shared
+
+	clone := sm.Clone()
+	clone.Insert(100, "appended") // appends to the clone's keys
+	clone.Insert(3, "inserted")   // shifts the clone's keys
+
+	want := []int{0, 2, 4, 6, 8}
+	if !slices.Equal(want, sm.keys) {
+		t.Errorf("original keys = %v, want %v", sm.keys, want)
+	}
+	if got := sm.keys[:cap(sm.keys)][len(sm.keys)]; 0 != got {
+		t.Errorf("original spare slot = %d, want 0", got)
+	}
+	if err := sm.Validate(); nil != err {
+		t.Errorf("Validate() = %v, want nil", err)
+	}
+	if got := len(clone.Keys()); 7 != got {
+		t.Errorf("len(clone.Keys()) = %d, want 7", got)
+	}
+} // Test_TSortedMap_Clone_keysAliasing()
+
+func Test_TSortedMap_KeysBatched(t *testing.T) {
+	sm := NewMap[int, string](false)
+	for key := range 7 {
+		sm.Insert(key, "v")
+	}
+
+	var batches [][]int
+	sm.KeysBatched(3, func(aBatch []int) bool {
+		batches = append(batches, slices.Clone(aBatch))
+		aBatch[0] = 99 // must not affect the map
+		return true
+	})
+	if 3 != len(batches) || !slices.Equal([]int{6}, batches[2]) {
+		t.Errorf("batches = %v, want [[0 1 2] [3 4 5] [6]]", batches)
+	}
+	if got := sm.Keys(); !slices.Equal([]int{0, 1, 2, 3, 4, 5, 6}, got) {
+		t.Errorf("Keys() = %v, want [0 1 2 3 4 5 6]", got)
+	}
+
+	var calls int
+	sm.KeysBatched(2, func([]int) bool {
+		calls++
+		return false
+	})
+	if 1 != calls {
+		t.Errorf("calls = %d, want 1", calls)
+	}
+} // Test_TSortedMap_KeysBatched()
+
+func Test_DiffIterate(t *testing.T) {
+	m1 := NewMap[int, string](false)
+	m1.Insert(1, "one")
+	m1.Insert(2, "two")
+	m1.Insert(3, "three")
+	m2 := NewMap[int, string](false)
+	m2.Insert(2, "two")
+	m2.Insert(3, "THREE")
+	m2.Insert(4, "four")
+
+	var got []string
+	DiffIterate(m1, m2, func(aOp string, aKey int, aOld, aNew string) bool {
+		got = append(got, fmt.Sprintf("%s %d %q %q", aOp, aKey, aOld, aNew))
+		return true
+	})
+	want := []string{
+		`removed 1 "one" ""`,
+		`changed 3 "three" "THREE"`,
+		`added 4 "" "four"`,
+	}
+	if !slices.Equal(want, got) {
+		t.Errorf("DiffIterate() = %v, want %v", got, want)
+	}
+} // Test_DiffIterate()
+
+func Test_DiffIterate_lockOrder(t *testing.T) {
+	m1 := NewMap[int, int](true)
+	m1.Insert(-1, 1)
+	m2 := NewMap[int, int](true)
+	m2.Insert(-1, 2)
+	// Holding the read locks a bit longer widens the deadlock window.
+	slow := func(string, int, int, int) bool {
+		time.Sleep(time.Microsecond)
+		return true
+	}
+
+	done := make(chan struct{})
+	go func() {
+		var wg sync.WaitGroup
+		for idx := range 1000 {
+			wg.Add(4)
+			go func() {
+				defer wg.Done()
+				DiffIterate(m1, m2, slow)
+			}()
+			go func() {
+				defer wg.Done()
+				DiffIterate(m2, m1, slow)
+			}()
+			// Pending writers block new readers, which turns
+			// inconsistent read lock ordering into a deadlock.
+			go func() {
+				defer wg.Done()
+				m1.Insert(idx, idx)
+			}()
+			go func() {
+				defer wg.Done()
+				m2.Insert(idx, idx)
+			}()
+		}
+		wg.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		t.Fatal("DiffIterate() deadlocked on concurrent opposite calls")
+	}
+} // Test_DiffIterate_lockOrder()
+
+func Test_TSortedMap_range_NaN(t *testing.T) {
+	nan := math.NaN()
+	all := func(float64, string) bool { return true }
+	tests := []struct {
+		name      string
+		low, high float64
+		wantKeys  []float64
+	}{
+		{"NaN low", nan, 3, []float64{}},
+		{"NaN high", 1, nan, []float64{}},
+		{"NaN both", nan, nan, []float64{}},
+		{"inverted", 3, 1, []float64{}},
+		{"regular", 2, 3, []float64{2, 3}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			newMap := func() *TSortedMap[float64, string] {
+				sm := NewMap[float64, string](false)
+				sm.Insert(1, "one")
+				sm.Insert(2, "two")
+				sm.Insert(3, "three")
+				return sm
+			}
+
+			sm := newMap()
+			if got := sm.RangeMap(tt.low, tt.high); len(tt.wantKeys) != len(got) {
+				t.Errorf("RangeMap() = %v, want keys %v", got, tt.wantKeys)
+			}
+			if got := sm.Snapshot().Range(tt.low, tt.high); len(tt.wantKeys) != len(got) {
+				t.Errorf("Snapshot().Range() = %v, want keys %v", got, tt.wantKeys)
+			}
+
+			var popped []float64
+			for _, entry := range sm.PopRange(tt.low, tt.high) {
+				popped = append(popped, entry.Key)
+			}
+			if !slices.Equal(tt.wantKeys, append([]float64{}, popped...)) {
+				t.Errorf("PopRange() keys = %v, want %v", popped, tt.wantKeys)
+			}
+			if got, want := len(sm.Keys()), 3-len(tt.wantKeys); want != got {
+				t.Errorf("len(Keys()) after PopRange() = %d, want %d", got, want)
+			}
+
+			sm = newMap()
+			if got := sm.DeleteRangeFunc(tt.low, tt.high, all); len(tt.wantKeys) != got {
+				t.Errorf("DeleteRangeFunc() = %d, want %d", got, len(tt.wantKeys))
+			}
+			if err := sm.Validate(); nil != err {
+				t.Errorf("Validate() = %v, want nil", err)
+			}
+		})
+	}
+} // Test_TSortedMap_range_NaN()
+
+// `newTestLRU()` returns an LRU map holding the keys 1 to 4 with the
+// access order 2, 4, 1, 3 (least recently used first).
+func newTestLRU() *TSortedMap[int, string] {
+	sm := NewLRUMap[int, string](true)
+	for _, key := range []int{1, 2, 3, 4} {
+		sm.Insert(key, fmt.Sprint(key))
+	}
+	sm.Get(2)
+	sm.Get(4)
+	sm.Get(1)
+	sm.Get(3)
+
+	return sm
+} // newTestLRU()
+
+// `evictAll()` empties `aMap` by `EvictLRU()` returning the evicted keys.
+func evictAll(aMap *TSortedMap[int, string]) []int {
+	result := []int{}
+	for {
+		key, value, ok := aMap.EvictLRU()
+		if !ok {
+			return result
+		}
+		if fmt.Sprint(key) != value {
+			return append(result, -1) // wrong value returned
+		}
+		result = append(result, key)
+	}
+} // evictAll()
+
+func Test_TSortedMap_EvictLRU(t *testing.T) {
+	sm := newTestLRU()
+
+	key, value, ok := sm.EvictLRU()
+	if !ok || (2 != key) || ("2" != value) {
+		t.Errorf("EvictLRU() = %d, %q, %v, want 2, \"2\", true", key, value, ok)
+	}
+	if _, ok := sm.Get(2); ok {
+		t.Error("Get(2) found the evicted key")
+	}
+	if got, want := sm.Keys(), []int{1, 3, 4}; !slices.Equal(want, got) {
+		t.Errorf("Keys() = %v, want %v", got, want)
+	}
+	if err := sm.Validate(); nil != err {
+		t.Errorf("Validate() = %v, want nil", err)
+	}
+
+	// Updating an entry counts as access, too.
+	sm.Insert(4, "4")
+	if got, want := evictAll(sm), []int{1, 3, 4}; !slices.Equal(want, got) {
+		t.Errorf("eviction order = %v, want %v", got, want)
+	}
+	if _, _, ok := sm.EvictLRU(); ok {
+		t.Error("EvictLRU() on empty map = true, want false")
+	}
+
+	// Maps not tracking the access order don't evict anything.
+	plain := NewMap[int, string](false)
+	plain.Insert(1, "1")
+	if _, _, ok := plain.EvictLRU(); ok {
+		t.Error("EvictLRU() on plain map = true, want false")
+	}
+	if got := len(plain.Keys()); 1 != got {
+		t.Errorf("len(Keys()) = %d, want 1", got)
+	}
+} // Test_TSortedMap_EvictLRU()
+
+func Test_TSortedMap_EvictLRU_Delete(t *testing.T) {
+	sm := newTestLRU()
+	sm.Delete(4)
+
+	if got, want := evictAll(sm), []int{2, 1, 3}; !slices.Equal(want, got) {
+		t.Errorf("eviction order = %v, want %v", got, want)
+	}
+
+	// A deleted and re-inserted key becomes the most recently used one.
+	sm = newTestLRU()
+	sm.Delete(2)
+	sm.Insert(2, "2")
+	if got, want := evictAll(sm), []int{4, 1, 3, 2}; !slices.Equal(want, got) {
+		t.Errorf("eviction order after re-insert = %v, want %v", got, want)
+	}
+} // Test_TSortedMap_EvictLRU_Delete()
+
+func Test_TSortedMap_EvictLRU_Clear(t *testing.T) {
+	sm := newTestLRU()
+	sm.Clear()
+
+	if _, _, ok := sm.EvictLRU(); ok {
+		t.Error("EvictLRU() after Clear() = true, want false")
+	}
+
+	sm.Insert(5, "5")
+	sm.Insert(6, "6")
+	if got, want := evictAll(sm), []int{5, 6}; !slices.Equal(want, got) {
+		t.Errorf("eviction order after Clear() = %v, want %v", got, want)
+	}
+} // Test_TSortedMap_EvictLRU_Clear()
+
+func Test_TSortedMap_EvictLRU_SplitAt(t *testing.T) {
+	sm := newTestLRU()
+	lower, upper := sm.SplitAt(3)
+
+	if got, want := evictAll(lower), []int{2, 1}; !slices.Equal(want, got) {
+		t.Errorf("lower eviction order = %v, want %v", got, want)
+	}
+	if got, want := evictAll(upper), []int{4, 3}; !slices.Equal(want, got) {
+		t.Errorf("upper eviction order = %v, want %v", got, want)
+	}
+
+	// The original map keeps its own access order.
+	if got, want := evictAll(sm), []int{2, 4, 1, 3}; !slices.Equal(want, got) {
+		t.Errorf("original eviction order = %v, want %v", got, want)
+	}
+} // Test_TSortedMap_EvictLRU_SplitAt()
+
+// `newTestExpiring()` returns an expiring map holding the keys 1 to 4,
+// all of them inserted an hour ago.
+func newTestExpiring() *TSortedMap[int, string] {
+	sm := NewExpiringMap[int, string](true)
+	for _, key := range []int{1, 2, 3, 4} {
+		sm.Insert(key, fmt.Sprint(key))
+	}
+	past := time.Now().Add(-time.Hour)
+	for key := range sm.stamps {
+		sm.stamps[key] = past
+	}
+
+	return sm
+} // newTestExpiring()
+
+func Test_TSortedMap_Purge(t *testing.T) {
+	sm := newTestExpiring()
+	sm.Insert(2, "two") // renews the insertion time
+	sm.Insert(5, "5")
+
+	if got := sm.Purge(time.Minute); 3 != got {
+		t.Errorf("Purge(1m) = %d, want 3", got)
+	}
+	if got, want := sm.Keys(), []int{2, 5}; !slices.Equal(want, got) {
+		t.Errorf("Keys() = %v, want %v", got, want)
+	}
+	if got := len(sm.stamps); 2 != got {
+		t.Errorf("len(stamps) = %d, want 2", got)
+	}
+	if err := sm.Validate(); nil != err {
+		t.Errorf("Validate() = %v, want nil", err)
+	}
+	if got := sm.Purge(time.Minute); 0 != got {
+		t.Errorf("second Purge(1m) = %d, want 0", got)
+	}
+
+	// Maps not recording insertion times don't purge anything.
+	plain := NewMap[int, string](false)
+	plain.Insert(1, "1")
+	if got := plain.Purge(-time.Hour); 0 != got {
+		t.Errorf("Purge() on plain map = %d, want 0", got)
+	}
+} // Test_TSortedMap_Purge()
+
+func Test_TSortedMap_SetValueAt_renews(t *testing.T) {
+	sm := newTestExpiring()
+	if !sm.SetValueAt(1, "two") {
+		t.Fatal("SetValueAt(1) = false, want true")
+	}
+	sm.Purge(time.Minute)
+	if got, want := sm.Keys(), []int{2}; !slices.Equal(want, got) {
+		t.Errorf("Keys() after Purge() = %v, want %v", got, want)
+	}
+
+	lru := newTestLRU() // access order 2, 4, 1, 3
+	lru.SetValueAt(1, "two")
+	if got, want := lruKeys(lru.lru), []int{4, 1, 3, 2}; !slices.Equal(want, got) {
+		t.Errorf("access order = %v, want %v", got, want)
+	}
+} // Test_TSortedMap_SetValueAt_renews()
+
+func Test_TSortedMap_ReplaceValue_renews(t *testing.T) {
+	sm := newTestExpiring()
+	sm.Insert(5, "3")
+	sm.stamps[5] = sm.stamps[1]
+	if got := sm.ReplaceValue("3", "three"); 2 != got {
+		t.Fatalf("ReplaceValue() = %d, want 2", got)
+	}
+	sm.Purge(time.Minute)
+	if got, want := sm.Keys(), []int{3, 5}; !slices.Equal(want, got) {
+		t.Errorf("Keys() after Purge() = %v, want %v", got, want)
+	}
+
+	lru := newTestLRU() // access order 2, 4, 1, 3
+	lru.Insert(5, "2")
+	lru.ReplaceValue("2", "two")
+	if got, want := lruKeys(lru.lru), []int{4, 1, 3, 2, 5}; !slices.Equal(want, got) {
+		t.Errorf("access order = %v, want %v", got, want)
+	}
+} // Test_TSortedMap_ReplaceValue_renews()
+
+/* EoF */
@@ -0,0 +1,287 @@
+/*
+Copyright ©  2024  M.Watermann, 10247 Berlin, Germany
+
+		All rights reserved
+	EMail : <support@mwat.de>
+*/
+package sortedlists
+
+import (
+	"cmp"
+	"slices"
+	"unsafe"
+)
+
+//lint:file-ignore ST1017 - I prefer Yoda conditions
+
+type (
+	// `TSortedSet` represents an ordered set of keys.
+	//
+	// It is backed by a `TSortedSlice` (instead of a `TSortedMap` with
+	// dummy values), so there's no per-entry overhead for values or a
+	// hash map while the slice's insertion and search logic is reused.
+	//
+	// All methods are optionally thread-safe and can be called concurrently.
+	TSortedSet[K cmp.Ordered] struct {
+		list *TSortedSlice[K]
+	}
+)
+
+// --------------------------------------------------------------------------
+// constructor functions
+
+// `NewSet()` creates a new, empty `TSortedSet`.
+//
+// Parameters:
+// - `aSafe`: Flag to decide whether the returned set should be
+// thread safe, i.e. use a `sync.RWMutex` in all methods.
+//
+// Returns:
+// - `*TSortedSet[K]`: A pointer to the newly created instance.
+func NewSet[K cmp.Ordered](aSafe bool) *TSortedSet[K] {
+	return &TSortedSet[K]{
+		list: NewSlice[K](nil, aSafe),
+	}
+} // NewSet()
+
+// --------------------------------------------------------------------------
+// helper functions
+
+// `intersectSorted()` returns the elements contained in both sorted lists.
+//
+// Parameters:
+// - `aList1`: The first sorted list.
+// - `aList2`: The second sorted list.
+//
+// Returns:
+// - `[]T`: The sorted intersection of both lists.
+func intersectSorted[T cmp.Ordered](aList1, aList2 []T) []T {
+	result := make([]T, 0, min(len(aList1), len(aList2)))
+
+	i, j := 0, 0
+	for (i < len(aList1)) && (j < len(aList2)) {
+		switch {
+		case aList1[i] < aList2[j]:
+			i++
+		case aList2[j] < aList1[i]:
+			j++
+		default: // equal elements
+			result = append(result, aList1[i])
+			i++
+			j++
+		}
+	}
+
+	return result
+} // intersectSorted()
+
+// `subtractSorted()` returns the elements of `aList1` not contained
+// in `aList2`.
+//
+// Parameters:
+// - `aList1`: The sorted list to subtract from.
+// - `aList2`: The sorted list of elements to remove.
+//
+// Returns:
+// - `[]T`: The sorted difference of both lists.
+func subtractSorted[T cmp.Ordered](aList1, aList2 []T) []T {
+	result := make([]T, 0, len(aList1))
+
+	i, j := 0, 0
+	for i < len(aList1) {
+		switch {
+		case (j == len(aList2)) || (aList1[i] < aList2[j]):
+			result = append(result, aList1[i])
+			i++
+		case aList2[j] < aList1[i]:
+			j++
+		default: // equal elements
+			i++
+			j++
+		}
+	}
+
+	return result
+} // subtractSorted()
+
+// --------------------------------------------------------------------------
+// methods of TSortedSet
+
+// `Add()` inserts `aKey` into the set.
+//
+// Parameters:
+// - `aKey`: The key to add.
+//
+// Returns:
+// - `bool`: `true` if `aKey` was added, or `false` if it was already present.
+func (set *TSortedSet[K]) Add(aKey K) bool {
+	return set.list.Insert(aKey)
+} // Add()
+
+// `combine()` applies `aFunc` to the keys of the current set and
+// `aSet` while holding both read locks.
+//
+// The locks are acquired in a consistent order (determined by the
+// sets' memory address) to rule out deadlocks.
+//
+// Parameters:
+// - `aSet`: The other set.
+// - `aFunc`: The function computing the resulting keys.
+//
+// Returns:
+// - `*TSortedSet[K]`: A new set (with the current set's thread-safety
+// setting) holding the resulting keys.
+func (set *TSortedSet[K]) combine(aSet *TSortedSet[K], aFunc func(a, b []K) []K) *TSortedSet[K] {
+	ss := set.list
+	first, second := ss, (*TSortedSlice[K])(nil)
+	if (nil != aSet) && (set != aSet) {
+		second = aSet.list
+		if uintptr(unsafe.Pointer(second)) < uintptr(unsafe.Pointer(first)) {
+			first, second = second, first
+		}
+	}
+	if first.safe {
+		first.mtx.RLock()
+		defer first.mtx.RUnlock()
+	}
+	if (nil != second) && second.safe {
+		second.mtx.RLock()
+		defer second.mtx.RUnlock()
+	}
+	var other []K
+	if nil != aSet {
+		other = aSet.list.data
+	}
+
+	return &TSortedSet[K]{
+		list: &TSortedSlice[K]{
+			data: aFunc(ss.data, other),
+			safe: ss.safe,
+		},
+	}
+} // combine()
+
+// `Contains()` checks whether `aKey` is part of the set.
+//
+// Parameters:
+// - `aKey`: The key to look up.
+//
+// Returns:
+// - `bool`: `true` if `aKey` was found, or `false` otherwise.
+func (set *TSortedSet[K]) Contains(aKey K) bool {
+	return set.list.Contains(aKey)
+} // Contains()
+
+// `Difference()` returns a new set holding the keys of the current set
+// which are not part of `aSet`.
+//
+// Parameters:
+// - `aSet`: The set whose keys are to be excluded.
+//
+// Returns:
+// - `*TSortedSet[K]`: The new set.
+func (set *TSortedSet[K]) Difference(aSet *TSortedSet[K]) *TSortedSet[K] {
+	return set.combine(aSet, subtractSorted[K])
+} // Difference()
+
+// `Intersection()` returns a new set holding the keys present in both
+// the current set and `aSet`.
+//
+// Parameters:
+// - `aSet`: The set to intersect with.
+//
+// Returns:
+// - `*TSortedSet[K]`: The new set.
+func (set *TSortedSet[K]) Intersection(aSet *TSortedSet[K]) *TSortedSet[K] {
+	return set.combine(aSet, intersectSorted[K])
+} // Intersection()
+
+// `IsSafe()` returns whether the current set is thread-safe.
+//
+// Returns:
+// - `bool`: An indicator for whether the current set is thread-safe.
+func (set *TSortedSet[K]) IsSafe() bool {
+	return set.list.safe
+} // IsSafe()
+
+// `Keys()` returns a copy of the set's keys in ascending order.
+//
+// Returns:
+// - `[]K`: The sorted keys.
+func (set *TSortedSet[K]) Keys() []K {
+	return set.list.Data()
+} // Keys()
+
+// `Len()` returns the number of keys in the set.
+//
+// Returns:
+// - `int`: The set's length.
+func (set *TSortedSet[K]) Len() int {
+	ss := set.list
+	if ss.safe {
+		ss.mtx.RLock()
+		defer ss.mtx.RUnlock()
+	}
+
+	return len(ss.data)
+} // Len()
+
+// `Range()` returns all keys within the closed interval `[aLow, aHigh]`.
+//
+// Parameters:
+// - `aLow`: The lower bound of the interval.
+// - `aHigh`: The upper bound of the interval.
+//
+// Returns:
+// - `[]K`: A copy of the keys within the interval.
+func (set *TSortedSet[K]) Range(aLow, aHigh K) []K {
+	if emptyRange(aLow, aHigh) {
+		return []K{}
+	}
+	ss := set.list
+	if ss.safe {
+		ss.mtx.RLock()
+		defer ss.mtx.RUnlock()
+	}
+
+	start, _ := slices.BinarySearch(ss.data, aLow)
+	end, found := slices.BinarySearch(ss.data, aHigh)
+	if found {
+		end++
+	}
+
+	return append([]K{}, ss.data[start:end]...)
+} // Range()
+
+// `Remove()` deletes `aKey` from the set.
+//
+// Parameters:
+// - `aKey`: The key to remove.
+//
+// Returns:
+// - `bool`: `true` if `aKey` was removed, or `false` otherwise.
+func (set *TSortedSet[K]) Remove(aKey K) bool {
+	return set.list.Delete(aKey)
+} // Remove()
+
+// `String()` implements the `fmt.Stringer` interface.
+//
+// Returns:
+// - `string`: The set's keys as a string.
+func (set *TSortedSet[K]) String() string {
+	return set.list.String()
+} // String()
+
+// `Union()` returns a new set holding the keys of both the current set
+// and `aSet`.
+//
+// Parameters:
+// - `aSet`: The set to unite with.
+//
+// Returns:
+// - `*TSortedSet[K]`: The new set.
+func (set *TSortedSet[K]) Union(aSet *TSortedSet[K]) *TSortedSet[K] {
+	return set.combine(aSet, mergeSorted[K])
+} // Union()
+
+/* EoF */
@@ -0,0 +1,140 @@
+/*
+Copyright ©  2024  M.Watermann, 10247 Berlin, Germany
+
+		All rights reserved
+	EMail : <support@mwat.de>
+*/
+package sortedlists
+
+import (
+	"math"
+	"slices"
+	"sync"
+	"testing"
+	"time"
+)
+
+//lint:file-ignore ST1017 - I prefer Yoda conditions
+
+func newTestSet(aKeys ...int) *TSortedSet[int] {
+	set := NewSet[int](true)
+	for _, key := range aKeys {
+		set.Add(key)
+	}
+
+	return set
+} // newTestSet()
+
+func Test_TSortedSet_combine(t *testing.T) {
+	s1 := newTestSet(1, 2, 3, 4)
+	s2 := newTestSet(3, 4, 5)
+	empty := newTestSet()
+
+	tests := []struct {
+		name string
+		got  *TSortedSet[int]
+		want []int
+	}{
+		{"union overlapping", s1.Union(s2), []int{1, 2, 3, 4, 5}},
+		{"union empty", s1.Union(empty), []int{1, 2, 3, 4}},
+		{"union of empty", empty.Union(s2), []int{3, 4, 5}},
+		{"union self", s1.Union(s1), []int{1, 2, 3, 4}},
+		{"union nil", s1.Union(nil), []int{1, 2, 3, 4}},
+		{"intersection overlapping", s1.Intersection(s2), []int{3, 4}},
+		{"intersection empty", s1.Intersection(empty), []int{}},
+		{"intersection of empty", empty.Intersection(s2), []int{}},
+		{"intersection self", s1.Intersection(s1), []int{1, 2, 3, 4}},
+		{"intersection nil", s1.Intersection(nil), []int{}},
+		{"difference overlapping", s1.Difference(s2), []int{1, 2}},
+		{"difference reversed", s2.Difference(s1), []int{5}},
+		{"difference empty", s1.Difference(empty), []int{1, 2, 3, 4}},
+		{"difference of empty", empty.Difference(s2), []int{}},
+		{"difference self", s1.Difference(s1), []int{}},
+		{"difference nil", s1.Difference(nil), []int{1, 2, 3, 4}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.got.Keys(); !slices.Equal(tt.want, got) {
+				t.Errorf("Keys() = %v, want %v", got, tt.want)
+			}
+			if !tt.got.IsSafe() {
+				t.Error("IsSafe() = false, want true")
+			}
+		})
+	}
+
+	// The operands must be left untouched.
+	if got, want := s1.Keys(), []int{1, 2, 3, 4}; !slices.Equal(want, got) {
+		t.Errorf("s1.Keys() = %v, want %v", got, want)
+	}
+	if got, want := s2.Keys(), []int{3, 4, 5}; !slices.Equal(want, got) {
+		t.Errorf("s2.Keys() = %v, want %v", got, want)
+	}
+} // Test_TSortedSet_combine()
+
+func Test_TSortedSet_combine_lockOrder(t *testing.T) {
+	s1 := newTestSet(1, 3, 5)
+	s2 := newTestSet(2, 4, 6)
+
+	done := make(chan struct{})
+	go func() {
+		var wg sync.WaitGroup
+		for idx := range 1000 {
+			wg.Add(4)
+			go func() {
+				defer wg.Done()
+				s1.Union(s2)
+			}()
+			go func() {
+				defer wg.Done()
+				s2.Intersection(s1)
+			}()
+			// Pending writers block new readers, which turns
+			// inconsistent read lock ordering into a deadlock.
+			go func() {
+				defer wg.Done()
+				s1.Add(idx)
+			}()
+			go func() {
+				defer wg.Done()
+				s2.Add(idx)
+			}()
+		}
+		wg.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		t.Fatal("combine() deadlocked on concurrent opposite calls")
+	}
+} // Test_TSortedSet_combine_lockOrder()
+
+func Test_TSortedSet_Range_NaN(t *testing.T) {
+	set := NewSet[float64](false)
+	for _, key := range []float64{1, 2, 3} {
+		set.Add(key)
+	}
+	nan := math.NaN()
+
+	tests := []struct {
+		name      string
+		low, high float64
+		want      []float64
+	}{
+		{"NaN low", nan, 3, []float64{}},
+		{"NaN high", 1, nan, []float64{}},
+		{"inverted", 3, 1, []float64{}},
+		{"regular", 2, 5, []float64{2, 3}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := set.Range(tt.low, tt.high); !slices.Equal(tt.want, got) {
+				t.Errorf("Range() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+} // Test_TSortedSet_Range_NaN()
+
+/* EoF */
@@ -8,15 +8,29 @@ package sortedlists
 
 import (
 	"cmp"
+	"context"
+	"database/sql/driver"
+	"encoding/json"
 	"fmt"
+	"io"
+	"iter"
+	"math"
+	"reflect"
 	"slices"
+	"sort"
 	"strings"
 	"sync"
+	"unsafe"
 )
 
 //lint:file-ignore ST1017 - I prefer Yoda conditions
 
 type (
+	// `TFloatPolicy` controls how a floating-point list handles the
+	// non-finite values `NaN`, `+Inf`, and `-Inf` on insertion
+	// (see `SetFloatPolicy()`).
+	TFloatPolicy uint8
+
 	// `TSortedSlice` represents a sorted slice of any ordered type.
 	//
 	// This is a generic type that accepts a type parameter:
@@ -30,14 +44,58 @@ type (
 	//
 	// All methods are optionally thread-safe and can be called concurrently.
 	TSortedSlice[T cmp.Ordered] struct {
-		data []T
-		mtx  sync.RWMutex
-		safe bool
+		data        []T
+		mtx         sync.RWMutex
+		epsilon     float64      // tolerance of float lookups (`0` = exact)
+		maxLen      int          // maximum number of elements (`0` = unlimited)
+		floatPolicy TFloatPolicy // handling of non-finite float elements
+		dropLargest bool         // evict the largest instead of the smallest element
+		safe        bool
 	}
 )
 
+const (
+	// `FloatReject` rejects `NaN` as well as `±Inf` (the default).
+	FloatReject TFloatPolicy = iota
+
+	// `FloatAllow` accepts `±Inf` which sort before respectively after
+	// all finite values; `NaN` is still rejected since it can't be ordered.
+	FloatAllow
+
+	// `FloatClamp` replaces `±Inf` by the largest finite value of the
+	// element type (with the respective sign); `NaN` is still rejected.
+	FloatClamp
+)
+
 // --------------------------------------------------------------------------
-// constructor function
+// constructor functions
+
+// `Collect()` creates a new `TSortedSlice` from the values of `aSeq`.
+//
+// All values are collected first and then sorted just once; duplicate
+// values and floating-point `NaN`s are dropped, and so are `±Inf` as
+// required by the default float policy (see `SetFloatPolicy()`).
+//
+// Parameters:
+// - `aSeq`: The sequence providing the list's elements.
+// - `aSafe`: Flag to decide whether the returned list should be
+// thread safe, i.e. use a `sync.RWMutex` in all methods.
+//
+// Returns:
+// - `*TSortedSlice[T]`: A pointer to the newly created instance.
+func Collect[T cmp.Ordered](aSeq iter.Seq[T], aSafe bool) *TSortedSlice[T] {
+	list := make([]T, 0, 32)
+	for value := range aSeq {
+		list = append(list, value)
+	}
+	ss := &TSortedSlice[T]{
+		data: make([]T, 0, len(list)),
+		safe: aSafe,
+	}
+	ss.insertMany(list)
+
+	return ss
+} // Collect()
 
 // `NewSlice()` creates a new `TSortedSlice`.
 //
@@ -69,269 +127,1870 @@ func NewSlice[T cmp.Ordered](aList []T, aSafe bool) *TSortedSlice[T] {
 	return ss
 } // NewSlice()
 
-// -------------------------------------------------------------------------
-// methods of TSortedSlice
+// --------------------------------------------------------------------------
+// helper functions
 
-// `Clear()` removes all entries in this list.
+// `emptyRange()` reports whether the closed interval `[aLow, aHigh]`
+// can't contain any element.
+//
+// This is the case for an inverted interval (i.e. `aLow > aHigh`) and
+// for a floating-point `NaN` bound, which can't be compared with the
+// list elements in a meaningful way.
+//
+// Parameters:
+// - `aLow`: The lower bound of the interval.
+// - `aHigh`: The upper bound of the interval.
 //
 // Returns:
-// - `*TSortedSlice[T]`: The cleared list instance.
-func (ss *TSortedSlice[T]) Clear() *TSortedSlice[T] {
-	if ss.safe {
-		ss.mtx.Lock()
-		defer ss.mtx.Unlock()
-	}
+// - `bool`: `true` if the interval is empty, or `false` otherwise.
+func emptyRange[T cmp.Ordered](aLow, aHigh T) bool {
+	return (aLow > aHigh) || isNaN(aLow) || isNaN(aHigh)
+} // emptyRange()
 
-	ss.data = make([]T, 0, 32)
-
-	return ss
-} // Clear()
-
-func (ss *TSortedSlice[T]) delete(aElement T) bool {
-	sLen := len(ss.data)
-	if 0 == sLen { // empty list
-		return false
-	}
-
-	// Find the index of the given element in the sorted slice
-	idx, ok := slices.BinarySearch(ss.data, aElement)
-	if !ok {
-		return false
+// `FoldWhile()` combines the elements of `aSrc` in ascending order into
+// a single accumulated value, stopping early on demand.
+//
+// For each element `aFunc` returns the updated accumulator and whether
+// to continue with the next element; as soon as it returns `false` the
+// accumulator is returned without visiting the remaining elements.
+//
+// NOTE: For a thread-safe list the read lock is held during the whole
+// iteration, so `aFunc` must not call any modifying methods of `aSrc`.
+//
+// Parameters:
+// - `aSrc`: The sorted slice whose elements are to be folded.
+// - `aInit`: The initial value of the accumulator.
+// - `aFunc`: The function combining the accumulator and an element.
+//
+// Returns:
+// - `A`: The final value of the accumulator.
+func FoldWhile[T cmp.Ordered, A any](aSrc *TSortedSlice[T], aInit A, aFunc func(A, T) (A, bool)) A {
+	if aSrc.safe {
+		aSrc.mtx.RLock()
+		defer aSrc.mtx.RUnlock()
 	}
 
-	if (idx < sLen) && (ss.data[idx] == aElement) {
-		// `aElement` found at index `idx`
-		if 0 == idx {
-			if 1 == sLen { // the only element
-				ss.data = make([]T, 0, 32)
-			} else { // a longer list
-				ss.data = ss.data[1:] // remove the first element
-			}
-		} else if (sLen - 1) == idx { // remove the last element
-			ss.data = ss.data[:idx]
-		} else { // remove element in the middle
-			ss.data = append(ss.data[:idx], ss.data[idx+1:]...)
+	result := aInit
+	for _, elem := range aSrc.data {
+		var more bool
+		if result, more = aFunc(result, elem); !more {
+			break
 		}
-		return true
 	}
 
-	return false
-} // delete()
+	return result
+} // FoldWhile()
 
-// `Delete()` removes an element from the sorted slice.
+// `isInf()` reports whether `aValue` is a floating-point infinity.
+//
+// Infinity is the only non-zero value equal to its own double (for
+// strings only the empty string equals itself concatenated), hence no
+// type inspection is required.
 //
 // Parameters:
-// - `aElement`: The element to remove from the list.
+// - `aValue`: The value to check.
 //
 // Returns:
-// - `bool`: `true` if `aElement` was removed, or `false` otherwise.
-func (ss *TSortedSlice[T]) Delete(aElement T) bool {
-	if ss.safe {
-		ss.mtx.Lock()
-		defer ss.mtx.Unlock()
-	}
+// - `bool`: `true` if `aValue` is `±Inf`, or `false` otherwise.
+func isInf[T cmp.Ordered](aValue T) bool {
+	var zero T // variable with its zero value
 
-	return ss.delete(aElement)
-} // Delete()
+	return (aValue+aValue == aValue) && (aValue != zero)
+} // isInf()
 
-// `Data()` returns the underlying data of the sorted slice.
+// `isNaN()` reports whether `aValue` is a floating-point "not-a-number".
+//
+// `NaN` is the only value not equal to itself which makes it unusable
+// for the binary searches the sorted lists are relying on.
+//
+// Parameters:
+// - `aValue`: The value to check.
 //
 // Returns:
-// - `[]T`: The underlying data of the sorted slice.
-func (ss *TSortedSlice[T]) Data() []T {
-	if ss.safe {
-		ss.mtx.RLock()
-		defer ss.mtx.RUnlock()
-	}
+// - `bool`: `true` if `aValue` is `NaN`, or `false` otherwise.
+func isNaN[T cmp.Ordered](aValue T) bool {
+	return aValue != aValue
+} // isNaN()
 
-	return append([]T{}, ss.data...)
-} // Data()
-
-// `Equals()` checks if the current sorted slice is equal to another
-// sorted slice.
+// `toFloat()` converts `aValue` to `float64` if its underlying type is
+// a floating-point type.
 //
-// The method compares the elements of the current sorted slice with the
-// elements of the given sorted slice. It returns `true` if both slices
-// contain the same elements in the same order, or `false` otherwise.
+// Parameters:
+// - `aValue`: The value to convert.
 //
-// If the slices are thread-safe, the method acquires the respective
-// read locks before performing the comparison.
+// Returns:
+// - `float64`: The converted value.
+// - `bool`: An indication whether `aValue` is a floating-point number.
+func toFloat[T cmp.Ordered](aValue T) (float64, bool) {
+	switch v := reflect.ValueOf(aValue); v.Kind() {
+	case reflect.Float32, reflect.Float64:
+		return v.Float(), true
+	default:
+		return 0, false
+	}
+} // toFloat()
+
+// `clampInf()` replaces an infinite `aValue` by the largest finite
+// value of its type with the same sign.
 //
 // Parameters:
-//   - `aList`: The sorted slice to compare with the current slice.
+// - `aValue`: The value to clamp.
 //
 // Returns:
-//   - `bool`: An indicator for whether the current slice is equal to `aList`.
-func (ss *TSortedSlice[T]) Equals(aList *TSortedSlice[T]) bool {
-	if ss.safe {
-		ss.mtx.RLock()
-		defer ss.mtx.RUnlock()
+// - `T`: The clamped value, or `aValue` if it's finite.
+func clampInf[T cmp.Ordered](aValue T) T {
+	v := reflect.ValueOf(&aValue).Elem()
+	switch v.Kind() {
+	case reflect.Float32, reflect.Float64:
+		f := v.Float()
+		if !math.IsInf(f, 0) {
+			break
+		}
+		limit := math.MaxFloat64
+		if reflect.Float32 == v.Kind() {
+			limit = math.MaxFloat32
+		}
+		if 0 > f {
+			limit = -limit
+		}
+		v.SetFloat(limit)
 	}
+
+	return aValue
+} // clampInf()
+
+// `Tally()` counts the occurrences of each distinct element of `aList`.
+//
+// Since equal elements are adjacent in the sorted list a single pass
+// suffices. The returned map uses the same thread-safety setting as
+// `aList`.
+//
+// Parameters:
+// - `aList`: The sorted slice whose elements are to be counted.
+//
+// Returns:
+// - `*TSortedMap[T, int]`: A map of the distinct elements and their count.
+func Tally[T cmp.Ordered](aList *TSortedSlice[T]) *TSortedMap[T, int] {
 	if aList.safe {
 		aList.mtx.RLock()
 		defer aList.mtx.RUnlock()
 	}
 
-	return slices.Equal(ss.data, aList.data)
-} // Equals()
-
-func (ss *TSortedSlice[T]) findIndex(aElement T) int {
-	sLen := len(ss.data)
-	if 0 == sLen {
-		return -1
+	result := NewMap[T, int](aList.safe)
+	for idx, elem := range aList.data {
+		if (0 < idx) && (aList.data[idx-1] == elem) {
+			result.data[elem]++
+			continue
+		}
+		// the elements are sorted, so the keys are as well
+		result.keys = append(result.keys, elem)
+		result.data[elem] = 1
 	}
 
-	// Find the index of the given element
-	idx, exists := slices.BinarySearch(ss.data, aElement)
-	if !exists {
-		return -1
+	return result
+} // Tally()
+
+// -------------------------------------------------------------------------
+// methods of TSortedSlice
+
+// `admit()` checks `aElement` against the list's float policy (see
+// `SetFloatPolicy()`).
+//
+// Parameters:
+// - `aElement`: The element to be inserted.
+//
+// Returns:
+// - `T`: The element to actually insert (i.e. possibly clamped).
+// - `bool`: An indication whether the element may be inserted.
+func (ss *TSortedSlice[T]) admit(aElement T) (T, bool) {
+	if isNaN(aElement) { // NaN would corrupt the list's ordering
+		return aElement, false
+	}
+	if isInf(aElement) {
+		switch ss.floatPolicy {
+		case FloatAllow:
+			return aElement, true
+		case FloatClamp:
+			return clampInf(aElement), true
+		default:
+			return aElement, false
+		}
 	}
 
-	if idx < sLen && ss.data[idx] == aElement {
-		return idx
+	return aElement, true
+} // admit()
+
+// `All()` returns an iterator over all list elements in ascending order.
+//
+// The iterator works on the live list data instead of a copy, so
+// nothing gets allocated and breaking out of the loop early saves the
+// cost of copying the remaining elements.
+// The trade-off: for a thread-safe list the read lock is held while
+// iterating, hence the loop body must not call any modifying methods
+// of this list (which would cause a deadlock). For a list which isn't
+// thread-safe, modifications inside the loop lead to undefined results.
+// Use `Data()` if a snapshot is required.
+//
+// Returns:
+// - `iter.Seq[T]`: The iterator over the list's elements.
+func (ss *TSortedSlice[T]) All() iter.Seq[T] {
+	return func(aYield func(T) bool) {
+		if ss.safe {
+			ss.mtx.RLock()
+			defer ss.mtx.RUnlock()
+		}
+
+		for _, elem := range ss.data {
+			if !aYield(elem) {
+				return
+			}
+		}
 	}
+} // All()
 
-	return -1 // aElement not found
-} // findIndex()
+// `Backward()` returns an iterator over all list elements in descending
+// order.
+//
+// Like `All()` the iterator works on the live list data, with the same
+// restrictions regarding modifications while iterating.
+//
+// Returns:
+// - `iter.Seq[T]`: The iterator over the list's elements.
+func (ss *TSortedSlice[T]) Backward() iter.Seq[T] {
+	return func(aYield func(T) bool) {
+		if ss.safe {
+			ss.mtx.RLock()
+			defer ss.mtx.RUnlock()
+		}
 
-// `FindIndex()` returns the list index of `aElement`.
+		for idx := len(ss.data) - 1; 0 <= idx; idx-- {
+			if !aYield(ss.data[idx]) {
+				return
+			}
+		}
+	}
+} // Backward()
+
+// `CeilingIndex()` returns the index of the smallest element greater
+// than or equal to `aValue`.
 //
-// If the `aElement` is not found, the method returns -1.
+// The index is derived from the binary search's insertion point, so no
+// second lookup is required to locate the ceiling element.
 //
 // Parameters:
-// - `aElement`: The list element to look up.
+// - `aValue`: The value to find the ceiling of.
 //
 // Returns:
-// - `int`: The index of `aID` in the list.
-func (ss *TSortedSlice[T]) FindIndex(aElement T) int {
+// - `int`: The index of the ceiling element, or -1 if there is none.
+// - `bool`: An indication whether a ceiling element exists.
+func (ss *TSortedSlice[T]) CeilingIndex(aValue T) (int, bool) {
 	if ss.safe {
 		ss.mtx.RLock()
 		defer ss.mtx.RUnlock()
 	}
 
-	return ss.findIndex(aElement)
-} // FindIndex()
+	if idx, _ := slices.BinarySearch(ss.data, aValue); idx < len(ss.data) {
+		return idx, true
+	}
 
-// `Get()` retrieves a value by its list index from the sorted slice.
+	return -1, false
+} // CeilingIndex()
+
+// `Chunk()` splits the list into consecutive chunks of `aSize` elements.
+//
+// Each chunk is a copy of the respective part of the list, hence sorted
+// itself; only the last chunk may hold fewer than `aSize` elements.
 //
 // Parameters:
-// - `aIndex`: The list index to use for returning the list element.
+// - `aSize`: The number of elements per chunk; must be greater than zero.
 //
 // Returns:
-// - `T`: The value associated with the `aIndex`.
-// - `bool`: An indication whether the index was found in the list.
-func (ss *TSortedSlice[T]) Get(aIndex int) (T, bool) {
+// - `[][]T`: The chunks in ascending order, or an empty list if `aSize` is invalid.
+func (ss *TSortedSlice[T]) Chunk(aSize int) [][]T {
+	if 0 >= aSize {
+		return [][]T{}
+	}
 	if ss.safe {
 		ss.mtx.RLock()
 		defer ss.mtx.RUnlock()
 	}
-	var result T // variable with its zero value
 
-	if aIndex < len(ss.data) {
-		return ss.data[aIndex], true
+	sLen := len(ss.data)
+	result := make([][]T, 0, (sLen+aSize-1)/aSize)
+	for start := 0; start < sLen; start += aSize {
+		end := min(start+aSize, sLen)
+		result = append(result, append(make([]T, 0, end-start), ss.data[start:end]...))
 	}
 
-	return result, false
-} // Get()
-
-func (ss *TSortedSlice[T]) insert(aElement T) bool {
-	sLen := len(ss.data)
-	if 0 == sLen { // empty list
-		ss.data = append(ss.data, aElement)
+	return result
+} // Chunk()
 
-		return true
+// `Clear()` removes all entries in this list.
+//
+// Returns:
+// - `*TSortedSlice[T]`: The cleared list instance.
+func (ss *TSortedSlice[T]) Clear() *TSortedSlice[T] {
+	if ss.safe {
+		ss.mtx.Lock()
+		defer ss.mtx.Unlock()
 	}
 
-	// find the insertion index using binary search
-	idx, exists := slices.BinarySearch(ss.data, aElement)
-	if !exists {
-		ss.data = append(ss.data, aElement)
+	ss.data = make([]T, 0, 32)
 
-		return true
-	}
+	return ss
+} // Clear()
 
-	if sLen == idx { // key not found
-		ss.data = append(ss.data, aElement) // add new element
-		return true
+// `Clone()` returns a copy of the current list.
+//
+// The copy holds its own copy of the elements and uses the same
+// configuration (e.g. thread-safety) as the current list, so it behaves
+// identically without sharing any data with the original.
+//
+// Returns:
+// - `*TSortedSlice[T]`: The new list instance.
+func (ss *TSortedSlice[T]) Clone() *TSortedSlice[T] {
+	if ss.safe {
+		ss.mtx.RLock()
+		defer ss.mtx.RUnlock()
 	}
-	if ss.data[idx] != aElement {
-		ss.data = append(ss.data, aElement) // make room for new element
-		copy(ss.data[idx+1:], ss.data[idx:])
-		ss.data[idx] = aElement
 
-		return true
+	return &TSortedSlice[T]{
+		data:        append(make([]T, 0, cap(ss.data)), ss.data...),
+		epsilon:     ss.epsilon,
+		maxLen:      ss.maxLen,
+		floatPolicy: ss.floatPolicy,
+		dropLargest: ss.dropLargest,
+		safe:        ss.safe,
 	}
+} // Clone()
 
-	return false
-} // insert()
-
-// `Insert()` adds an element to the sorted slice while maintaining order.
+// `CompactEx()` removes duplicate elements from the list and returns
+// the removed copies.
 //
-// Parameters:
-// - `aElement` The element to insert to the list.
+// Normally the list doesn't hold any duplicates, but they might have
+// crept in e.g. through the slice handed to `NewSlice()` (see
+// `Validate()`). The list is expected to be sorted, so equal elements
+// are adjacent and a single pass suffices.
 //
 // Returns:
-// - `bool`: `true` if `aElement` was inserted, or `false` otherwise.
-func (ss *TSortedSlice[T]) Insert(aElement T) bool {
+// - `[]T`: The removed elements, one entry per removed copy.
+func (ss *TSortedSlice[T]) CompactEx() []T {
 	if ss.safe {
 		ss.mtx.Lock()
 		defer ss.mtx.Unlock()
 	}
 
-	return ss.insert(aElement)
-} // Insert()
+	result := []T{}
+	sLen := len(ss.data)
+	if 2 > sLen {
+		return result
+	}
 
-// `IsSafe()` returns whether the current slice is thread-safe.
+	kept := 1
+	for idx := 1; idx < sLen; idx++ {
+		if elem := ss.data[idx]; elem == ss.data[kept-1] {
+			result = append(result, elem)
+		} else {
+			ss.data[kept] = elem
+			kept++
+		}
+	}
+	clear(ss.data[kept:]) // release the freed slots
+	ss.data = ss.data[:kept]
+
+	return result
+} // CompactEx()
+
+// `concat()` merges the sorted elements of `aList` into the list.
 //
-// A `TSortedSlice` instance is thread-safe if it was created with the `aSafe`
-// flag set to `true` when calling the constructor function `NewSlice()`.
-// In a thread-safe instance, all methods can be called concurrently
-// without causing data corruption or race conditions.
+// Parameters:
+// - `aList`: The sorted elements to be added.
 //
 // Returns:
-//   - bool: An indicator for whether the current slice is thread-safe.
-func (ss *TSortedSlice[T]) IsSafe() bool {
-	return ss.safe
-} // IsSafe()
-
-func (ss *TSortedSlice[T]) rename(aOldValue, aNewValue T) bool {
-	if (0 == len(ss.data)) || (aOldValue == aNewValue) {
-		return false
+// - `int`: The number of new elements kept in the list.
+// - `int`: The number of new elements evicted right away because of
+// the list's length limit (see `SetMaxLen()`).
+func (ss *TSortedSlice[T]) concat(aList []T) (rAdded, rEvicted int) {
+	if 0 == len(aList) {
+		return
 	}
 
-	idx := ss.findIndex(aOldValue)
-	if 0 > idx { // ID not found
-		return ss.insert(aNewValue)
-	}
+	oldData := ss.data // `mergeSorted()` returns a new slice
+	ss.data = mergeSorted(oldData, aList)
+	rAdded = len(ss.data) - len(oldData)
 
-	if !ss.insert(aNewValue) {
-		// This should only happen it there's an OOM problem.
-		// Hence we just replace the aOldValue by aNewValue and
-		// sort the list again.
-		if ss.data[idx] != aNewValue {
-			ss.data[idx] = aNewValue
-			slices.Sort(ss.data) // ascending
-			return true
+	// Elements evicted right away don't count as added.
+	for _, elem := range ss.trim() {
+		if _, found := slices.BinarySearch(oldData, elem); !found {
+			rEvicted++
 		}
-		return false
 	}
+	rAdded -= rEvicted
 
-	return ss.delete(aOldValue)
-} // rename()
+	return
+} // concat()
 
-// Rename changes an element in the sorted slice and maintains order.
-func (ss *TSortedSlice[T]) Rename(aOldValue, aNewValue T) bool {
-	if ss.safe {
-		ss.mtx.Lock()
-		defer ss.mtx.Unlock()
+// `Concat()` merges all elements of `aList` into the current list.
+//
+// Unlike creating a new union of both lists this method modifies the
+// current list in place. Elements already present are not added again,
+// and floating-point `±Inf` are handled according to the current list's
+// float policy (see `SetFloatPolicy()`).
+//
+// The locks of both lists are acquired in a consistent order
+// (determined by their memory address) so that concurrent calls in
+// opposite directions can't cause a deadlock.
+//
+// Parameters:
+// - `aList`: The sorted slice whose elements are to be added.
+//
+// Returns:
+// - `int`: The number of elements actually added.
+func (ss *TSortedSlice[T]) Concat(aList *TSortedSlice[T]) int {
+	if (nil == aList) || (ss == aList) {
+		return 0
 	}
 
-	return ss.rename(aOldValue, aNewValue)
-} // Rename()
-
-func (ss *TSortedSlice[T]) string() string {
-	if 0 == len(ss.data) {
-		return "[]"
+	lockDest := func() {
+		if ss.safe {
+			ss.mtx.Lock()
+		}
+	}
+	lockSrc := func() {
+		if aList.safe {
+			aList.mtx.RLock()
+		}
+	}
+	if uintptr(unsafe.Pointer(aList)) < uintptr(unsafe.Pointer(ss)) {
+		lockSrc()
+		lockDest()
+	} else {
+		lockDest()
+		lockSrc()
+	}
+	if ss.safe {
+		defer ss.mtx.Unlock()
+	}
+	if aList.safe {
+		defer aList.mtx.RUnlock()
+	}
+
+	result, _ := ss.insertMany(aList.data)
+
+	return result
+} // Concat()
+
+// `Contains()` checks whether `aElement` is part of the list.
+//
+// For a floating-point list with a tolerance set (see `SetEpsilon()`)
+// an element within that tolerance counts as a match.
+//
+// Parameters:
+// - `aElement`: The element to look up.
+//
+// Returns:
+// - `bool`: `true` if `aElement` was found, or `false` otherwise.
+func (ss *TSortedSlice[T]) Contains(aElement T) bool {
+	if ss.safe {
+		ss.mtx.RLock()
+		defer ss.mtx.RUnlock()
+	}
+
+	return 0 <= ss.nearIndex(aElement)
+} // Contains()
+
+// `CopyTo()` copies the list's elements into `aDest`.
+//
+// Like the builtin `copy()` function it copies `min(len(aDest), len(list))`
+// elements, starting with the smallest one. No memory is allocated.
+//
+// Parameters:
+// - `aDest`: The caller owned slice to fill.
+//
+// Returns:
+// - `int`: The number of elements copied.
+func (ss *TSortedSlice[T]) CopyTo(aDest []T) int {
+	if ss.safe {
+		ss.mtx.RLock()
+		defer ss.mtx.RUnlock()
+	}
+
+	return copy(aDest, ss.data)
+} // CopyTo()
+
+func (ss *TSortedSlice[T]) delete(aElement T) bool {
+	if 0 == len(ss.data) { // empty list
+		return false
+	}
+
+	// Find the index of the given element in the sorted slice
+	idx, ok := slices.BinarySearch(ss.data, aElement)
+	if !ok {
+		return false
+	}
+
+	// `slices.Delete()` handles the first, last, and middle elements
+	// alike, keeps the list's capacity, and zeroes the freed slot.
+	ss.data = slices.Delete(ss.data, idx, idx+1)
+
+	return true
+} // delete()
+
+// `Delete()` removes an element from the sorted slice.
+//
+// Parameters:
+// - `aElement`: The element to remove from the list.
+//
+// Returns:
+// - `bool`: `true` if `aElement` was removed, or `false` otherwise.
+func (ss *TSortedSlice[T]) Delete(aElement T) bool {
+	if ss.safe {
+		ss.mtx.Lock()
+		defer ss.mtx.Unlock()
+	}
+
+	return ss.delete(aElement)
+} // Delete()
+
+// `Data()` returns the underlying data of the sorted slice.
+//
+// Returns:
+// - `[]T`: The underlying data of the sorted slice.
+func (ss *TSortedSlice[T]) Data() []T {
+	if ss.safe {
+		ss.mtx.RLock()
+		defer ss.mtx.RUnlock()
+	}
+
+	return append([]T{}, ss.data...)
+} // Data()
+
+// `DataChan()` streams the list's elements in sorted order over a channel.
+//
+// A snapshot of the elements is taken while holding the read lock, so
+// the lock isn't held while sending. The returned channel is closed
+// after the last element was sent or when `aCtx` got cancelled.
+//
+// Parameters:
+// - `aCtx`: The context to stop the streaming early.
+//
+// Returns:
+// - `<-chan T`: The channel delivering the list's elements.
+func (ss *TSortedSlice[T]) DataChan(aCtx context.Context) <-chan T {
+	data := ss.Data()
+	result := make(chan T)
+
+	go func() {
+		defer close(result)
+
+		for _, elem := range data {
+			select {
+			case <-aCtx.Done():
+				return
+			case result <- elem:
+			}
+		}
+	}()
+
+	return result
+} // DataChan()
+
+// `Drain()` returns all elements and empties the list.
+//
+// Both operations are done while holding the write lock, so each element
+// is returned exactly once even if other goroutines concurrently add
+// new elements.
+//
+// Returns:
+// - `[]T`: All list elements in sorted order.
+func (ss *TSortedSlice[T]) Drain() []T {
+	if ss.safe {
+		ss.mtx.Lock()
+		defer ss.mtx.Unlock()
+	}
+
+	result := ss.data
+	if nil == result {
+		result = []T{}
+	}
+	ss.data = make([]T, 0, 32)
+
+	return result
+} // Drain()
+
+// `Each()` calls `aFunc` for each element in ascending order and stops
+// at the first error returned.
+//
+// A snapshot of the elements is taken while holding the read lock (see
+// `Data()`), so the lock isn't held while calling `aFunc`. Hence `aFunc`
+// may take its time (e.g. doing I/O) and may even modify the list; such
+// modifications don't affect the current iteration.
+//
+// Parameters:
+// - `aFunc`: The function to call with each list element.
+//
+// Returns:
+// - `error`: The first error returned by `aFunc`, or `nil`.
+func (ss *TSortedSlice[T]) Each(aFunc func(T) error) error {
+	for _, elem := range ss.Data() {
+		if err := aFunc(elem); nil != err {
+			return err
+		}
+	}
+
+	return nil
+} // Each()
+
+// `Equal()` checks whether the current list holds the same elements as
+// `aList`.
+//
+// Only the logical sequence of elements is compared, while the lists'
+// capacity and thread-safety setting are ignored. Since the structure
+// contains a mutex `reflect.DeepEqual()` is not suitable to compare two
+// lists; in tests use this method (or its alias `Equals()`) instead, e.g.
+//
+//	if !got.Equal(want) {
+//		t.Errorf("got %v, want %v", got, want)
+//	}
+//
+// Parameters:
+//   - `aList`: The sorted slice to compare with the current slice.
+//
+// Returns:
+//   - `bool`: An indicator for whether both lists hold the same elements.
+func (ss *TSortedSlice[T]) Equal(aList *TSortedSlice[T]) bool {
+	if nil == aList {
+		return false
+	}
+	if ss == aList {
+		return true
+	}
+
+	return ss.Equals(aList)
+} // Equal()
+
+// `Equals()` checks if the current sorted slice is equal to another
+// sorted slice.
+//
+// The method compares the elements of the current sorted slice with the
+// elements of the given sorted slice. It returns `true` if both slices
+// contain the same elements in the same order, or `false` otherwise.
+//
+// If the slices are thread-safe, the method acquires the respective
+// read locks before performing the comparison.
+//
+// Parameters:
+//   - `aList`: The sorted slice to compare with the current slice.
+//
+// Returns:
+//   - `bool`: An indicator for whether the current slice is equal to `aList`.
+func (ss *TSortedSlice[T]) Equals(aList *TSortedSlice[T]) bool {
+	if ss.safe {
+		ss.mtx.RLock()
+		defer ss.mtx.RUnlock()
+	}
+	if aList.safe {
+		aList.mtx.RLock()
+		defer aList.mtx.RUnlock()
+	}
+
+	return slices.Equal(ss.data, aList.data)
+} // Equals()
+
+// `Find()` returns the smallest element satisfying `aFunc`.
+//
+// The list is scanned in ascending order, so this method is O(n).
+// For monotonic predicates consider `FindMonotonic()` instead.
+//
+// Parameters:
+// - `aFunc`: The predicate to check the list elements with.
+//
+// Returns:
+// - `T`: The first element for which `aFunc` returned `true`.
+// - `bool`: An indication whether a matching element was found.
+func (ss *TSortedSlice[T]) Find(aFunc func(T) bool) (T, bool) {
+	if ss.safe {
+		ss.mtx.RLock()
+		defer ss.mtx.RUnlock()
+	}
+	var result T // variable with its zero value
+
+	for _, elem := range ss.data {
+		if aFunc(elem) {
+			return elem, true
+		}
+	}
+
+	return result, false
+} // Find()
+
+func (ss *TSortedSlice[T]) findIndex(aElement T) int {
+	sLen := len(ss.data)
+	if 0 == sLen {
+		return -1
+	}
+
+	// Find the index of the given element
+	idx, exists := slices.BinarySearch(ss.data, aElement)
+	if !exists {
+		return -1
+	}
+
+	if idx < sLen && ss.data[idx] == aElement {
+		return idx
+	}
+
+	return -1 // aElement not found
+} // findIndex()
+
+// `FindIndex()` returns the list index of `aElement`.
+//
+// If the `aElement` is not found, the method returns -1.
+//
+// For a floating-point list with a tolerance set (see `SetEpsilon()`)
+// the index of the element closest to `aElement` within that tolerance
+// is returned.
+//
+// Parameters:
+// - `aElement`: The list element to look up.
+//
+// Returns:
+// - `int`: The index of `aID` in the list.
+func (ss *TSortedSlice[T]) FindIndex(aElement T) int {
+	if ss.safe {
+		ss.mtx.RLock()
+		defer ss.mtx.RUnlock()
+	}
+
+	return ss.nearIndex(aElement)
+} // FindIndex()
+
+// `FindMonotonic()` returns the smallest element satisfying `aFunc`
+// using a binary search.
+//
+// NOTE: `aFunc` must be monotonic, i.e. if it returns `true` for an
+// element it must return `true` for all larger elements as well
+// (like e.g. `func(x int) bool { return x >= 42 }`).
+// Otherwise the result is undefined; use `Find()` for arbitrary
+// predicates.
+//
+// Parameters:
+// - `aFunc`: The monotonic predicate to check the list elements with.
+//
+// Returns:
+// - `T`: The first element for which `aFunc` returned `true`.
+// - `bool`: An indication whether a matching element was found.
+func (ss *TSortedSlice[T]) FindMonotonic(aFunc func(T) bool) (T, bool) {
+	if ss.safe {
+		ss.mtx.RLock()
+		defer ss.mtx.RUnlock()
+	}
+	var result T // variable with its zero value
+
+	idx := sort.Search(len(ss.data), func(i int) bool {
+		return aFunc(ss.data[i])
+	})
+	if idx < len(ss.data) {
+		return ss.data[idx], true
+	}
+
+	return result, false
+} // FindMonotonic()
+
+// `FirstIndexOf()` returns the list index of the first element equal
+// to `aElement`.
+//
+// Since the list doesn't hold duplicates the result equals that of
+// `FindIndex()`; the lower bound of the run of equal elements is
+// determined nonetheless.
+//
+// Parameters:
+// - `aElement`: The list element to look up.
+//
+// Returns:
+// - `int`: The index of `aElement` in the list, or -1 if not found.
+func (ss *TSortedSlice[T]) FirstIndexOf(aElement T) int {
+	if ss.safe {
+		ss.mtx.RLock()
+		defer ss.mtx.RUnlock()
+	}
+
+	// find the lower bound, i.e. the first element not less than `aElement`
+	idx := sort.Search(len(ss.data), func(i int) bool {
+		return ss.data[i] >= aElement
+	})
+
+	if (idx < len(ss.data)) && (ss.data[idx] == aElement) {
+		return idx
+	}
+
+	return -1 // aElement not found
+} // FirstIndexOf()
+
+// `FloorIndex()` returns the index of the largest element less than or
+// equal to `aValue`.
+//
+// The index is derived from the binary search's insertion point, so no
+// second lookup is required to locate the floor element.
+//
+// Parameters:
+// - `aValue`: The value to find the floor of.
+//
+// Returns:
+// - `int`: The index of the floor element, or -1 if there is none.
+// - `bool`: An indication whether a floor element exists.
+func (ss *TSortedSlice[T]) FloorIndex(aValue T) (int, bool) {
+	if ss.safe {
+		ss.mtx.RLock()
+		defer ss.mtx.RUnlock()
+	}
+
+	idx, found := slices.BinarySearch(ss.data, aValue)
+	if !found {
+		idx-- // the insertion point is behind the floor element
+	}
+	if 0 <= idx {
+		return idx, true
+	}
+
+	return -1, false
+} // FloorIndex()
+
+// `Get()` retrieves a value by its list index from the sorted slice.
+//
+// Parameters:
+// - `aIndex`: The list index to use for returning the list element.
+//
+// Returns:
+// - `T`: The value associated with the `aIndex`.
+// - `bool`: An indication whether the index was found in the list.
+func (ss *TSortedSlice[T]) Get(aIndex int) (T, bool) {
+	if ss.safe {
+		ss.mtx.RLock()
+		defer ss.mtx.RUnlock()
+	}
+	var result T // variable with its zero value
+
+	if aIndex < len(ss.data) {
+		return ss.data[aIndex], true
+	}
+
+	return result, false
+} // Get()
+
+// `GetMany()` retrieves several values by their list indices from the
+// sorted slice.
+//
+// All lookups are done while holding the read lock only once, hence the
+// returned values represent a consistent snapshot of the list.
+// Indices out of range yield the zero value and `false`.
+//
+// Parameters:
+// - `aIndices`: The list indices to use for returning the list elements.
+//
+// Returns:
+// - `[]T`: The values associated with the `aIndices`.
+// - `[]bool`: Indications whether the respective index was found in the list.
+func (ss *TSortedSlice[T]) GetMany(aIndices ...int) ([]T, []bool) {
+	if ss.safe {
+		ss.mtx.RLock()
+		defer ss.mtx.RUnlock()
+	}
+	values := make([]T, len(aIndices))
+	found := make([]bool, len(aIndices))
+
+	sLen := len(ss.data)
+	for idx, index := range aIndices {
+		if (0 <= index) && (index < sLen) {
+			values[idx] = ss.data[index]
+			found[idx] = true
+		}
+	}
+
+	return values, found
+} // GetMany()
+
+// `HasRange()` checks whether any list element lies within the closed
+// interval `[aLow, aHigh]`.
+//
+// No elements are copied; only a single binary search is performed.
+// An inverted interval (i.e. `aLow > aHigh`) or a `NaN` bound contains
+// no elements.
+//
+// Parameters:
+// - `aLow`: The lower bound of the interval.
+// - `aHigh`: The upper bound of the interval.
+//
+// Returns:
+// - `bool`: `true` if an element lies within the interval, or `false` otherwise.
+func (ss *TSortedSlice[T]) HasRange(aLow, aHigh T) bool {
+	if emptyRange(aLow, aHigh) {
+		return false
+	}
+	if ss.safe {
+		ss.mtx.RLock()
+		defer ss.mtx.RUnlock()
+	}
+
+	idx, _ := slices.BinarySearch(ss.data, aLow)
+
+	return (idx < len(ss.data)) && (ss.data[idx] <= aHigh)
+} // HasRange()
+
+// `IndexOfFunc()` returns the index of the first element satisfying
+// `aFunc`.
+//
+// The list is scanned in ascending order, so this method is O(n) but
+// works with arbitrary predicates, unlike the binary search used by
+// `FindIndex()` or `FindMonotonic()`.
+//
+// Parameters:
+// - `aFunc`: The predicate to check the list elements with.
+//
+// Returns:
+// - `int`: The index of the first matching element, or -1 if none matched.
+func (ss *TSortedSlice[T]) IndexOfFunc(aFunc func(T) bool) int {
+	if ss.safe {
+		ss.mtx.RLock()
+		defer ss.mtx.RUnlock()
+	}
+
+	return slices.IndexFunc(ss.data, aFunc)
+} // IndexOfFunc()
+
+func (ss *TSortedSlice[T]) insert(aElement T) bool {
+	aElement, ok := ss.admit(aElement)
+	if !ok {
+		return false
+	}
+
+	// find the insertion index using binary search
+	idx, exists := slices.BinarySearch(ss.data, aElement)
+	if exists { // element already in list
+		return false
+	}
+	ss.data = slices.Insert(ss.data, idx, aElement)
+
+	return true
+} // insert()
+
+// `Insert()` adds an element to the sorted slice while maintaining order.
+//
+// A floating-point `NaN` is rejected since it can't be ordered; the
+// handling of `±Inf` depends on the list's float policy (see
+// `SetFloatPolicy()`).
+//
+// If the list's length is limited (see `SetMaxLen()`) and the list is
+// full, a boundary element gets evicted. Use `InsertEvict()` to learn
+// which element that was.
+//
+// Parameters:
+// - `aElement` The element to insert to the list.
+//
+// Returns:
+// - `bool`: `true` if `aElement` was inserted, or `false` otherwise.
+func (ss *TSortedSlice[T]) Insert(aElement T) bool {
+	if ss.safe {
+		ss.mtx.Lock()
+		defer ss.mtx.Unlock()
+	}
+
+	return ss.insertCapped(aElement)
+} // Insert()
+
+// `insertCapped()` adds `aElement` to the list and evicts the surplus
+// element if the list's length is limited (see `SetMaxLen()`).
+//
+// Parameters:
+// - `aElement` The element to insert to the list.
+//
+// Returns:
+// - `bool`: `true` if `aElement` was inserted and kept, or `false` otherwise.
+func (ss *TSortedSlice[T]) insertCapped(aElement T) bool {
+	aElement, ok := ss.admit(aElement) // the value actually inserted
+	if !ok || !ss.insert(aElement) {
+		return false
+	}
+	if evicted := ss.trim(); (0 < len(evicted)) && (evicted[0] == aElement) {
+		return false // the new element itself was beyond the limit
+	}
+
+	return true
+} // insertCapped()
+
+// `InsertAll()` adds all elements of `aList` to the current list.
+//
+// This method is an alias of `Concat()`.
+//
+// Parameters:
+// - `aList`: The sorted slice whose elements are to be inserted.
+//
+// Returns:
+// - `int`: The number of elements actually inserted.
+func (ss *TSortedSlice[T]) InsertAll(aList *TSortedSlice[T]) int {
+	return ss.Concat(aList)
+} // InsertAll()
+
+// `InsertErr()` adds an element to the sorted slice like `Insert()`
+// but reports why an element wasn't inserted.
+//
+// If the list is full and another element gets evicted to make room
+// for `aElement`, the insertion counts as successful.
+//
+// Parameters:
+// - `aElement` The element to insert to the list.
+//
+// Returns:
+// - `error`: `ErrInvalidValue` if `aElement` was rejected (e.g. `NaN`,
+// see `SetFloatPolicy()`), `ErrDuplicate` if it was already present,
+// `ErrCapacityExceeded` if it's beyond the list's length limit (see
+// `SetMaxLen()`), or `nil` if it was inserted.
+func (ss *TSortedSlice[T]) InsertErr(aElement T) error {
+	if ss.safe {
+		ss.mtx.Lock()
+		defer ss.mtx.Unlock()
+	}
+
+	aElement, ok := ss.admit(aElement)
+	if !ok {
+		return ErrInvalidValue
+	}
+	if _, exists := slices.BinarySearch(ss.data, aElement); exists {
+		return ErrDuplicate
+	}
+	ss.insert(aElement)
+	if evicted := ss.trim(); (0 < len(evicted)) && (evicted[0] == aElement) {
+		return ErrCapacityExceeded
+	}
+
+	return nil
+} // InsertErr()
+
+// `InsertEvict()` adds an element to the sorted slice and returns the
+// element evicted, if any, because of the list's length limit (see
+// `SetMaxLen()`).
+//
+// If the list is full and `aElement` itself is beyond the limit, it is
+// returned as the evicted element, i.e. the list remains unchanged.
+//
+// Parameters:
+// - `aElement` The element to insert to the list.
+//
+// Returns:
+// - `T`: The evicted element.
+// - `bool`: An indication whether an element was evicted.
+func (ss *TSortedSlice[T]) InsertEvict(aElement T) (T, bool) {
+	if ss.safe {
+		ss.mtx.Lock()
+		defer ss.mtx.Unlock()
+	}
+	var result T // variable with its zero value
+
+	if ss.insert(aElement) {
+		if evicted := ss.trim(); 0 < len(evicted) {
+			return evicted[0], true
+		}
+	}
+
+	return result, false
+} // InsertEvict()
+
+// `insertMany()` adds all `aElements` to the sorted slice
+// (see `InsertMany()`).
+//
+// The list's float policy is applied, so the write lock must be held
+// when calling this method.
+//
+// Parameters:
+// - `aElements`: The elements to insert to the list.
+//
+// Returns:
+// - `int`: The number of elements actually inserted and not evicted.
+// - `int`: The number of duplicates skipped.
+func (ss *TSortedSlice[T]) insertMany(aElements []T) (rAdded, rDuplicates int) {
+	list := make([]T, 0, len(aElements))
+	for _, elem := range aElements {
+		if elem, ok := ss.admit(elem); ok {
+			list = append(list, elem)
+		}
+	}
+	if 0 == len(list) {
+		return
+	}
+	slices.Sort(list) // ascending
+	lLen := len(list)
+	list = slices.Compact(list)
+
+	rAdded, evicted := ss.concat(list)
+	rDuplicates = (lLen - len(list)) + (len(list) - rAdded - evicted)
+
+	return
+} // insertMany()
+
+// `InsertMany()` adds all `aElements` to the sorted slice.
+//
+// The given elements are sorted and compacted just once and then merged
+// into the list in linear time. Floating-point `NaN`s are ignored, and
+// `±Inf` are handled according to the list's float policy (see
+// `SetFloatPolicy()`).
+//
+// If the list's length is limited (see `SetMaxLen()`) the surplus
+// elements are evicted after merging.
+//
+// Parameters:
+// - `aElements`: The elements to insert to the list.
+//
+// Returns:
+// - `int`: The number of elements actually inserted and not evicted.
+// - `int`: The number of duplicates skipped, i.e. elements either
+// already present in the list or occurring more than once in `aElements`.
+func (ss *TSortedSlice[T]) InsertMany(aElements ...T) (rAdded, rDuplicates int) {
+	if ss.safe {
+		ss.mtx.Lock()
+		defer ss.mtx.Unlock()
+	}
+
+	return ss.insertMany(aElements)
+} // InsertMany()
+
+// `InsertSorted()` merges the already sorted elements of `aSorted` into
+// the list.
+//
+// PRECONDITION: `aSorted` must be in strictly ascending order, i.e.
+// sorted and without duplicates. Then no sorting is needed and the
+// elements are merged in linear time directly into the list's backing
+// array (growing it once if necessary). The precondition (as well as
+// the float policy, see `SetFloatPolicy()`) is verified by a cheap
+// linear scan; if it doesn't hold, the elements are handled like in
+// `InsertMany()` instead.
+//
+// If the list's length is limited (see `SetMaxLen()`) the surplus
+// elements are evicted after merging.
+//
+// Parameters:
+// - `aSorted`: The sorted elements to insert to the list.
+//
+// Returns:
+// - `int`: The number of elements actually inserted and not evicted.
+func (ss *TSortedSlice[T]) InsertSorted(aSorted []T) int {
+	if 0 == len(aSorted) {
+		return 0
+	}
+	if ss.safe {
+		ss.mtx.Lock()
+		defer ss.mtx.Unlock()
+	}
+
+	for idx, elem := range aSorted {
+		if e, ok := ss.admit(elem); !ok || (e != elem) ||
+			((0 < idx) && !(aSorted[idx-1] < elem)) {
+			rAdded, _ := ss.insertMany(aSorted)
+			return rAdded
+		}
+	}
+
+	// Count the new elements first to grow the backing array just once.
+	sLen, added := len(ss.data), 0
+	for i, j := 0, 0; j < len(aSorted); {
+		switch {
+		case (i == sLen) || (aSorted[j] < ss.data[i]):
+			added++
+			j++
+		case ss.data[i] < aSorted[j]:
+			i++
+		default: // already present
+			i++
+			j++
+		}
+	}
+	if 0 == added {
+		return 0
+	}
+
+	// Keep the old elements if some are going to be evicted right away,
+	// to tell which of the evicted ones were new.
+	var oldData []T
+	if (0 < ss.maxLen) && (sLen+added > ss.maxLen) {
+		oldData = slices.Clone(ss.data)
+	}
+
+	// Merge from the back so no element is overwritten before it's moved.
+	ss.data = slices.Grow(ss.data, added)[:sLen+added]
+	i, k := sLen-1, sLen+added-1
+	for j := len(aSorted) - 1; 0 <= j; {
+		switch {
+		case (0 <= i) && (aSorted[j] < ss.data[i]):
+			ss.data[k] = ss.data[i]
+			i--
+			k--
+		case (0 <= i) && (aSorted[j] == ss.data[i]):
+			j-- // already present
+		default:
+			ss.data[k] = aSorted[j]
+			j--
+			k--
+		}
+	}
+	for _, elem := range ss.trim() {
+		if _, found := slices.BinarySearch(oldData, elem); !found {
+			added-- // evicted new element
+		}
+	}
+
+	return added
+} // InsertSorted()
+
+// `IsSafe()` returns whether the current slice is thread-safe.
+//
+// A `TSortedSlice` instance is thread-safe if it was created with the `aSafe`
+// flag set to `true` when calling the constructor function `NewSlice()`.
+// In a thread-safe instance, all methods can be called concurrently
+// without causing data corruption or race conditions.
+//
+// Returns:
+//   - bool: An indicator for whether the current slice is thread-safe.
+func (ss *TSortedSlice[T]) IsSafe() bool {
+	return ss.safe
+} // IsSafe()
+
+// `IterateReverse()` allows iteration over the list in reverse order,
+// i.e. from the largest element down to the smallest.
+//
+// The iteration stops as soon as `aFunc` returns `false`.
+//
+// NOTE: For a thread-safe list the read lock is held during the whole
+// iteration, so `aFunc` must not call any other methods of this list.
+// Otherwise a deadlock might occur.
+//
+// Parameters:
+// - `aFunc`: The function to call with each list element.
+//
+// Returns:
+// - `*TSortedSlice[T]`: The list instance, allowing method chaining.
+func (ss *TSortedSlice[T]) IterateReverse(aFunc func(T) bool) *TSortedSlice[T] {
+	if ss.safe {
+		ss.mtx.RLock()
+		defer ss.mtx.RUnlock()
+	}
+
+	for idx := len(ss.data) - 1; 0 <= idx; idx-- {
+		if !aFunc(ss.data[idx]) {
+			break
+		}
+	}
+
+	return ss
+} // IterateReverse()
+
+// `LastIndexOf()` returns the list index of the last element equal
+// to `aElement`.
+//
+// Since the list doesn't hold duplicates the result equals that of
+// `FindIndex()`; the upper bound of the run of equal elements is
+// determined nonetheless.
+//
+// Parameters:
+// - `aElement`: The list element to look up.
+//
+// Returns:
+// - `int`: The index of `aElement` in the list, or -1 if not found.
+func (ss *TSortedSlice[T]) LastIndexOf(aElement T) int {
+	if ss.safe {
+		ss.mtx.RLock()
+		defer ss.mtx.RUnlock()
+	}
+
+	// find the upper bound, i.e. the first element greater than `aElement`
+	idx := sort.Search(len(ss.data), func(i int) bool {
+		return ss.data[i] > aElement
+	}) - 1
+
+	if (0 <= idx) && (ss.data[idx] == aElement) {
+		return idx
+	}
+
+	return -1 // aElement not found
+} // LastIndexOf()
+
+// `nearIndex()` returns the index of the element closest to `aElement`
+// within the list's tolerance (see `SetEpsilon()`).
+//
+// Parameters:
+// - `aElement`: The element to look up.
+//
+// Returns:
+// - `int`: The index of the closest matching element, or -1 if not found.
+func (ss *TSortedSlice[T]) nearIndex(aElement T) int {
+	idx, exists := slices.BinarySearch(ss.data, aElement)
+	if exists {
+		return idx
+	}
+	if (0 == ss.epsilon) || isNaN(aElement) {
+		return -1
+	}
+	target, ok := toFloat(aElement)
+	if !ok {
+		return -1
+	}
+
+	// Candidates are found on both sides of the insertion point only.
+	result, best := -1, ss.epsilon
+	for i := idx - 1; 0 <= i; i-- {
+		value, _ := toFloat(ss.data[i])
+		diff := target - value
+		if diff > ss.epsilon {
+			break
+		}
+		if diff <= best {
+			result, best = i, diff
+		}
+	}
+	for i := idx; i < len(ss.data); i++ {
+		value, _ := toFloat(ss.data[i])
+		diff := value - target
+		if diff > ss.epsilon {
+			break
+		}
+		if diff < best {
+			result, best = i, diff
+		}
+	}
+
+	return result
+} // nearIndex()
+
+// `PeekMax()` returns the last (i.e. largest) element of the sorted
+// slice without removing it.
+//
+// Returns:
+// - `T`: The largest element in the list.
+// - `bool`: An indication whether the list contains any elements.
+func (ss *TSortedSlice[T]) PeekMax() (T, bool) {
+	if ss.safe {
+		ss.mtx.RLock()
+		defer ss.mtx.RUnlock()
+	}
+	var result T // variable with its zero value
+
+	if sLen := len(ss.data); 0 < sLen {
+		return ss.data[sLen-1], true
+	}
+
+	return result, false
+} // PeekMax()
+
+// `PeekMin()` returns the first (i.e. smallest) element of the sorted
+// slice without removing it.
+//
+// Returns:
+// - `T`: The smallest element in the list.
+// - `bool`: An indication whether the list contains any elements.
+func (ss *TSortedSlice[T]) PeekMin() (T, bool) {
+	if ss.safe {
+		ss.mtx.RLock()
+		defer ss.mtx.RUnlock()
+	}
+	var result T // variable with its zero value
+
+	if 0 < len(ss.data) {
+		return ss.data[0], true
+	}
+
+	return result, false
+} // PeekMin()
+
+// `PopRange()` removes all elements within the closed interval
+// `[aLow, aHigh]` and returns them.
+//
+// Since the matching elements form a contiguous block of the sorted
+// list they are copied out and removed in a single step while holding
+// the write lock.
+//
+// Parameters:
+// - `aLow`: The lower bound of the interval.
+// - `aHigh`: The upper bound of the interval.
+//
+// Returns:
+// - `[]T`: The removed elements in ascending order.
+func (ss *TSortedSlice[T]) PopRange(aLow, aHigh T) []T {
+	if emptyRange(aLow, aHigh) {
+		return []T{}
+	}
+	if ss.safe {
+		ss.mtx.Lock()
+		defer ss.mtx.Unlock()
+	}
+
+	start, _ := slices.BinarySearch(ss.data, aLow)
+	end, found := slices.BinarySearch(ss.data, aHigh)
+	if found {
+		end++
+	}
+
+	result := append([]T{}, ss.data[start:end]...)
+	if 0 < len(result) {
+		ss.data = slices.Delete(ss.data, start, end)
+	}
+
+	return result
+} // PopRange()
+
+// `ReadJSON()` replaces the list's elements by the JSON array read
+// from `aReader`.
+//
+// The array is decoded element by element, so the whole document never
+// has to be buffered. The elements are sorted and deduplicated after
+// loading. The write lock is held while loading; in case of an error
+// the list remains unchanged.
+//
+// Parameters:
+// - `aReader`: The source of the JSON encoded list.
+//
+// Returns:
+// - `error`: A possible read or decoding error, or `nil`.
+func (ss *TSortedSlice[T]) ReadJSON(aReader io.Reader) error {
+	if ss.safe {
+		ss.mtx.Lock()
+		defer ss.mtx.Unlock()
+	}
+
+	dec := json.NewDecoder(aReader)
+	if tok, err := dec.Token(); nil != err {
+		return err
+	} else if json.Delim('[') != tok {
+		return fmt.Errorf("expected JSON array, got %v", tok)
+	}
+
+	list := make([]T, 0, 32)
+	for dec.More() {
+		var element T
+		if err := dec.Decode(&element); nil != err {
+			return err
+		}
+		list = append(list, element)
+	}
+	if _, err := dec.Token(); nil != err { // closing bracket
+		return err
+	}
+
+	slices.Sort(list) // ascending
+	ss.data = slices.Compact(list)
+	ss.trim()
+
+	return nil
+} // ReadJSON()
+
+// `RemoveIf()` deletes all elements for which `aFunc` returns `true`
+// and returns them.
+//
+// Collecting and removing the elements is done in a single stable pass
+// while holding the write lock, so `aFunc` must not call any methods of
+// the current list.
+//
+// Parameters:
+// - `aFunc`: The function deciding whether an element should be removed.
+//
+// Returns:
+// - `[]T`: The removed elements in ascending order.
+func (ss *TSortedSlice[T]) RemoveIf(aFunc func(T) bool) []T {
+	result := []T{}
+	if nil == aFunc {
+		return result
+	}
+	if ss.safe {
+		ss.mtx.Lock()
+		defer ss.mtx.Unlock()
+	}
+
+	ss.data = slices.DeleteFunc(ss.data, func(aElement T) bool {
+		if aFunc(aElement) {
+			result = append(result, aElement)
+			return true
+		}
+		return false
+	})
+
+	return result
+} // RemoveIf()
+
+func (ss *TSortedSlice[T]) rename(aOldValue, aNewValue T) bool {
+	aNewValue, ok := ss.admit(aNewValue)
+	if !ok || (0 == len(ss.data)) || (aOldValue == aNewValue) {
+		return false
+	}
+
+	idx := ss.findIndex(aOldValue)
+	if 0 > idx { // ID not found
+		return ss.insertCapped(aNewValue)
+	}
+
+	if !ss.insert(aNewValue) {
+		// This should only happen it there's an OOM problem.
+		// Hence we just replace the aOldValue by aNewValue and
+		// sort the list again.
+		if ss.data[idx] != aNewValue {
+			ss.data[idx] = aNewValue
+			slices.Sort(ss.data) // ascending
+			return true
+		}
+		return false
+	}
+
+	return ss.delete(aOldValue)
+} // rename()
+
+// Rename changes an element in the sorted slice and maintains order.
+func (ss *TSortedSlice[T]) Rename(aOldValue, aNewValue T) bool {
+	if ss.safe {
+		ss.mtx.Lock()
+		defer ss.mtx.Unlock()
+	}
+
+	return ss.rename(aOldValue, aNewValue)
+} // Rename()
+
+// `ReplaceTwo()` replaces the elements at the positions `aIndex1` and
+// `aIndex2` by `aVal1` and `aVal2` respectively and restores the list's
+// order afterwards.
+//
+// If a new value equals another element of the list the duplicate is
+// removed, so the list may shrink. If both indices are the same, the
+// element ends up as `aVal2`.
+//
+// Parameters:
+// - `aIndex1`: The position of the first element to replace.
+// - `aIndex2`: The position of the second element to replace.
+// - `aVal1`: The new value for position `aIndex1`.
+// - `aVal2`: The new value for position `aIndex2`.
+//
+// Returns:
+// - `bool`: `true` if the elements were replaced, or `false` if an index
+// is out of range or a value is rejected (see `SetFloatPolicy()`).
+func (ss *TSortedSlice[T]) ReplaceTwo(aIndex1, aIndex2 int, aVal1, aVal2 T) bool {
+	if ss.safe {
+		ss.mtx.Lock()
+		defer ss.mtx.Unlock()
+	}
+
+	sLen := len(ss.data)
+	if (0 > aIndex1) || (aIndex1 >= sLen) || (0 > aIndex2) || (aIndex2 >= sLen) {
+		return false
+	}
+	val1, ok1 := ss.admit(aVal1)
+	val2, ok2 := ss.admit(aVal2)
+	if !ok1 || !ok2 {
+		return false
+	}
+
+	ss.data[aIndex1] = val1
+	ss.data[aIndex2] = val2
+	slices.Sort(ss.data) // ascending
+	ss.data = slices.Compact(ss.data)
+
+	return true
+} // ReplaceTwo()
+
+// `Reset()` replaces the list's contents by the elements of `aList`,
+// reusing the current instance.
+//
+// This allows e.g. keeping instances in a `sync.Pool` without
+// allocating a new list (and its mutex) for each use. The elements of
+// `aList` are copied into the existing backing array if its capacity
+// suffices; they are sorted and deduplicated. The list's configuration
+// (length limit, tolerance, float policy) is reset to the defaults of a
+// new instance first, so floating-point `NaN`s and `±Inf` are dropped
+// (see `SetFloatPolicy()`).
+//
+// NOTE: Changing the thread-safety setting is only safe while no other
+// goroutine is using the list, e.g. right after taking it from a pool.
+//
+// Parameters:
+// - `aList`: The new elements of the list.
+// - `aSafe`: Flag to decide whether the list should be thread safe.
+//
+// Returns:
+// - `*TSortedSlice[T]`: The reset list instance.
+func (ss *TSortedSlice[T]) Reset(aList []T, aSafe bool) *TSortedSlice[T] {
+	if ss.safe {
+		ss.mtx.Lock()
+		defer ss.mtx.Unlock()
+	}
+
+	ss.epsilon = 0
+	ss.maxLen = 0
+	ss.floatPolicy = FloatReject
+	ss.dropLargest = false
+
+	data := ss.data[:0]
+	if cap(data) < len(aList) {
+		data = make([]T, 0, len(aList))
+	}
+	for _, elem := range aList {
+		if elem, ok := ss.admit(elem); ok {
+			data = append(data, elem)
+		}
+	}
+	slices.Sort(data) // ascending
+	data = slices.Compact(data)
+	clear(data[len(data):cap(data)]) // release stale elements
+
+	ss.data = data
+	ss.safe = aSafe
+
+	return ss
+} // Reset()
+
+// `Resort()` sorts and deduplicates the list's elements again.
+//
+// This is a cheap way to restore the list's consistency (see `Validate()`)
+// after its data was modified outside of this type's methods, e.g.
+// through the slice handed to `NewSlice()`.
+//
+// Returns:
+// - `*TSortedSlice[T]`: The repaired list instance.
+func (ss *TSortedSlice[T]) Resort() *TSortedSlice[T] {
+	if ss.safe {
+		ss.mtx.Lock()
+		defer ss.mtx.Unlock()
+	}
+
+	ss.data = slices.DeleteFunc(ss.data, isNaN[T])
+	slices.Sort(ss.data) // ascending
+	ss.data = slices.Compact(ss.data)
+
+	return ss
+} // Resort()
+
+// `Scan()` implements the `sql.Scanner` interface.
+//
+// The database value is expected to be a JSON array as written by
+// `Value()`. The list's current elements get replaced by the scanned
+// ones which are sorted and deduplicated. A `nil` value results in an
+// empty list.
+//
+// Parameters:
+// - `aSrc`: The database value (`[]byte`, `string`, or `nil`).
+//
+// Returns:
+// - `error`: A possible decoding error, or `nil`.
+func (ss *TSortedSlice[T]) Scan(aSrc any) error {
+	var list []T
+
+	switch src := aSrc.(type) {
+	case nil:
+		// empty list
+	case []byte:
+		if err := json.Unmarshal(src, &list); nil != err {
+			return err
+		}
+	case string:
+		if err := json.Unmarshal([]byte(src), &list); nil != err {
+			return err
+		}
+	default:
+		return fmt.Errorf("can't scan type %T into TSortedSlice", aSrc)
+	}
+
+	if ss.safe {
+		ss.mtx.Lock()
+		defer ss.mtx.Unlock()
+	}
+
+	if 0 == len(list) {
+		ss.data = make([]T, 0, 32)
+		return nil
+	}
+	slices.Sort(list) // ascending
+	ss.data = slices.Compact(list)
+	ss.trim()
+
+	return nil
+} // Scan()
+
+// `Search()` looks up `aElement` in the list using a binary search.
+//
+// Like `slices.BinarySearch()` it returns the index where `aElement`
+// is found or would be inserted, allowing callers to implement their
+// own range logic.
+//
+// Parameters:
+// - `aElement`: The element to look up.
+//
+// Returns:
+// - `int`: The (insertion) index of `aElement`.
+// - `bool`: An indication whether `aElement` is present in the list.
+func (ss *TSortedSlice[T]) Search(aElement T) (int, bool) {
+	if ss.safe {
+		ss.mtx.RLock()
+		defer ss.mtx.RUnlock()
+	}
+
+	return slices.BinarySearch(ss.data, aElement)
+} // Search()
+
+// `Set()` returns the list's elements as a standard Go set.
+//
+// Returns:
+// - `map[T]struct{}`: A set holding all elements of the list.
+func (ss *TSortedSlice[T]) Set() map[T]struct{} {
+	if ss.safe {
+		ss.mtx.RLock()
+		defer ss.mtx.RUnlock()
+	}
+
+	result := make(map[T]struct{}, len(ss.data))
+	for _, elem := range ss.data {
+		result[elem] = struct{}{}
+	}
+
+	return result
+} // Set()
+
+// `SetEpsilon()` sets the tolerance used to look up elements of a
+// floating-point list.
+//
+// With a tolerance greater than zero `Contains()` and `FindIndex()`
+// treat elements within `aEps` of the requested value as equal.
+// NOTE: This affects lookups only. Insertions still reject exact
+// duplicates only, so the list may hold several elements within `aEps`
+// of each other; lookups then report the closest one.
+// For lists of non-float types the tolerance has no effect.
+//
+// Parameters:
+// - `aEps`: The comparison tolerance; `0` (or less) means exact matches.
+//
+// Returns:
+// - `*TSortedSlice[T]`: The list instance, allowing method chaining.
+func (ss *TSortedSlice[T]) SetEpsilon(aEps float64) *TSortedSlice[T] {
+	if ss.safe {
+		ss.mtx.Lock()
+		defer ss.mtx.Unlock()
+	}
+
+	if !(0 < aEps) { // also catches NaN
+		aEps = 0
+	}
+	ss.epsilon = aEps
+
+	return ss
+} // SetEpsilon()
+
+// `SetFloatPolicy()` sets how a floating-point list handles non-finite
+// elements on insertion.
+//
+// By default (`FloatReject`) `NaN` and `±Inf` are rejected to protect
+// the list's ordering; `FloatAllow` accepts `±Inf` while `FloatClamp`
+// replaces them by the element type's largest finite value of the same
+// sign. `NaN` is rejected under every policy since it can't be ordered.
+// Elements already in the list are not affected, and for lists of
+// non-float types the policy has no effect.
+//
+// Parameters:
+// - `aPolicy`: The handling of non-finite elements.
+//
+// Returns:
+// - `*TSortedSlice[T]`: The list instance, allowing method chaining.
+func (ss *TSortedSlice[T]) SetFloatPolicy(aPolicy TFloatPolicy) *TSortedSlice[T] {
+	if ss.safe {
+		ss.mtx.Lock()
+		defer ss.mtx.Unlock()
+	}
+
+	ss.floatPolicy = aPolicy
+
+	return ss
+} // SetFloatPolicy()
+
+// `SetMaxLen()` limits the number of elements the list may hold.
+//
+// Whenever an insertion exceeds the limit, the largest (`aDropLargest`
+// is `true`) or the smallest element gets evicted, so the list acts as
+// a bottom-N or top-N buffer, respectively.
+// If the list currently holds more than `aMax` elements, it is trimmed
+// immediately.
+//
+// Parameters:
+// - `aMax`: The maximum number of elements; `0` (or less) means unlimited.
+// - `aDropLargest`: Whether to evict the largest instead of the smallest element.
+//
+// Returns:
+// - `*TSortedSlice[T]`: The list instance, allowing method chaining.
+func (ss *TSortedSlice[T]) SetMaxLen(aMax int, aDropLargest bool) *TSortedSlice[T] {
+	if ss.safe {
+		ss.mtx.Lock()
+		defer ss.mtx.Unlock()
+	}
+
+	if 0 > aMax {
+		aMax = 0
+	}
+	ss.maxLen = aMax
+	ss.dropLargest = aDropLargest
+	ss.trim()
+
+	return ss
+} // SetMaxLen()
+
+// `Snapshot()` returns a read-only view of the list's current elements.
+//
+// The view holds a frozen copy of the elements, so later modifications
+// of the list don't affect it, and it provides non-modifying methods
+// only.
+//
+// Returns:
+// - `TReadOnlySortedSlice[T]`: The immutable view of the list.
+func (ss *TSortedSlice[T]) Snapshot() TReadOnlySortedSlice[T] {
+	return TReadOnlySortedSlice[T]{
+		data: ss.Data(),
+	}
+} // Snapshot()
+
+// `SplitAt()` divides the list into two new lists at `aPivot`.
+//
+// The first returned list holds all elements less than `aPivot`, the
+// second one all elements greater than or equal to `aPivot`.
+// Both new lists use the same configuration as the current one
+// (thread-safety, length limit, tolerance, float policy).
+// The current list is not modified.
+//
+// Parameters:
+// - `aPivot`: The value at which to split the list.
+//
+// Returns:
+// - `*TSortedSlice[T]`: A list with the elements `< aPivot`.
+// - `*TSortedSlice[T]`: A list with the elements `>= aPivot`.
+func (ss *TSortedSlice[T]) SplitAt(aPivot T) (rLower, rUpper *TSortedSlice[T]) {
+	if ss.safe {
+		ss.mtx.RLock()
+		defer ss.mtx.RUnlock()
+	}
+
+	idx, _ := slices.BinarySearch(ss.data, aPivot)
+
+	// Both halves are already sorted, so there's no need to sort again.
+	rLower = &TSortedSlice[T]{
+		data:        append(make([]T, 0, idx), ss.data[:idx]...),
+		epsilon:     ss.epsilon,
+		maxLen:      ss.maxLen,
+		floatPolicy: ss.floatPolicy,
+		dropLargest: ss.dropLargest,
+		safe:        ss.safe,
+	}
+	rUpper = &TSortedSlice[T]{
+		data:        append(make([]T, 0, len(ss.data)-idx), ss.data[idx:]...),
+		epsilon:     ss.epsilon,
+		maxLen:      ss.maxLen,
+		floatPolicy: ss.floatPolicy,
+		dropLargest: ss.dropLargest,
+		safe:        ss.safe,
+	}
+
+	return
+} // SplitAt()
+
+func (ss *TSortedSlice[T]) string() string {
+	if 0 == len(ss.data) {
+		return "[]"
 	}
 
 	var builder strings.Builder
@@ -365,4 +2024,202 @@ func (ss *TSortedSlice[T]) String() string {
 	return ss.string()
 } // String()
 
+// `trim()` evicts the elements exceeding the list's length limit.
+//
+// Returns:
+// - `[]T`: The evicted elements, or `nil` if none.
+func (ss *TSortedSlice[T]) trim() []T {
+	if (0 == ss.maxLen) || (len(ss.data) <= ss.maxLen) {
+		return nil
+	}
+
+	var result []T
+	if ss.dropLargest {
+		result = slices.Clone(ss.data[ss.maxLen:])
+		clear(ss.data[ss.maxLen:]) // release the freed slots
+		ss.data = ss.data[:ss.maxLen]
+	} else {
+		excess := len(ss.data) - ss.maxLen
+		result = slices.Clone(ss.data[:excess])
+		ss.data = slices.Delete(ss.data, 0, excess)
+	}
+
+	return result
+} // trim()
+
+// `TrimAbove()` removes all elements greater than `aValue`.
+//
+// Parameters:
+// - `aValue`: The largest value to keep.
+//
+// Returns:
+// - `int`: The number of elements removed.
+func (ss *TSortedSlice[T]) TrimAbove(aValue T) int {
+	if isNaN(aValue) {
+		return 0
+	}
+	if ss.safe {
+		ss.mtx.Lock()
+		defer ss.mtx.Unlock()
+	}
+
+	idx, found := slices.BinarySearch(ss.data, aValue)
+	if found {
+		idx++
+	}
+	result := len(ss.data) - idx
+	clear(ss.data[idx:]) // release the freed slots
+	ss.data = ss.data[:idx]
+
+	return result
+} // TrimAbove()
+
+// `TrimBelow()` removes all elements less than `aValue`.
+//
+// Parameters:
+// - `aValue`: The smallest value to keep.
+//
+// Returns:
+// - `int`: The number of elements removed.
+func (ss *TSortedSlice[T]) TrimBelow(aValue T) int {
+	if isNaN(aValue) {
+		return 0
+	}
+	if ss.safe {
+		ss.mtx.Lock()
+		defer ss.mtx.Unlock()
+	}
+
+	idx, _ := slices.BinarySearch(ss.data, aValue)
+	if 0 < idx {
+		ss.data = slices.Delete(ss.data, 0, idx)
+	}
+
+	return idx
+} // TrimBelow()
+
+// `Validate()` checks the internal consistency of the list.
+//
+// The list is expected to be sorted in ascending order without any
+// duplicate elements. This might not be the case e.g. if the slice
+// given to `NewSlice()` was modified by the caller afterwards.
+//
+// Returns:
+// - `error`: A description of the first inconsistency found, or `nil`.
+func (ss *TSortedSlice[T]) Validate() error {
+	if ss.safe {
+		ss.mtx.RLock()
+		defer ss.mtx.RUnlock()
+	}
+
+	for idx := 1; idx < len(ss.data); idx++ {
+		if prev, elem := ss.data[idx-1], ss.data[idx]; !(prev < elem) {
+			if prev == elem {
+				return fmt.Errorf("duplicate element '%v' at index %d", elem, idx)
+			}
+			return fmt.Errorf("element '%v' at index %d is not less than element '%v' at index %d",
+				prev, idx-1, elem, idx)
+		}
+	}
+
+	return nil
+} // Validate()
+
+// `Value()` implements the `driver.Valuer` interface.
+//
+// The list's elements are stored as a JSON array.
+//
+// Returns:
+// - `driver.Value`: The JSON encoded list.
+// - `error`: A possible encoding error, or `nil`.
+func (ss *TSortedSlice[T]) Value() (driver.Value, error) {
+	if ss.safe {
+		ss.mtx.RLock()
+		defer ss.mtx.RUnlock()
+	}
+
+	data := ss.data
+	if nil == data {
+		data = []T{}
+	}
+	result, err := json.Marshal(data)
+	if nil != err {
+		return nil, err
+	}
+
+	return string(result), nil
+} // Value()
+
+// `Windows()` returns all contiguous windows of `aSize` elements.
+//
+// The windows overlap: the first one starts at the smallest element and
+// each following window is shifted by one element. Each window is a copy
+// of the respective part of the list, hence sorted itself, which makes
+// e.g. windowed minimum, maximum or median cheap to determine.
+//
+// Parameters:
+// - `aSize`: The number of elements per window; must be greater than zero.
+//
+// Returns:
+// - `[][]T`: The windows, or an empty list if the list is shorter than `aSize`.
+func (ss *TSortedSlice[T]) Windows(aSize int) [][]T {
+	if 0 >= aSize {
+		return [][]T{}
+	}
+	if ss.safe {
+		ss.mtx.RLock()
+		defer ss.mtx.RUnlock()
+	}
+
+	sLen := len(ss.data)
+	if sLen < aSize {
+		return [][]T{}
+	}
+
+	result := make([][]T, 0, sLen-aSize+1)
+	for start := 0; start+aSize <= sLen; start++ {
+		result = append(result, append(make([]T, 0, aSize), ss.data[start:start+aSize]...))
+	}
+
+	return result
+} // Windows()
+
+// `WriteJSON()` writes the list as a JSON array to `aWriter`.
+//
+// The elements are encoded one by one and streamed directly to
+// `aWriter`, so no buffer holding the whole document is needed.
+// The read lock is held until all elements are written. Each element
+// is followed by a newline (as written by `json.Encoder`) which is
+// valid JSON whitespace.
+//
+// Parameters:
+// - `aWriter`: The destination of the JSON encoded list.
+//
+// Returns:
+// - `error`: A possible encoding or write error, or `nil`.
+func (ss *TSortedSlice[T]) WriteJSON(aWriter io.Writer) error {
+	if ss.safe {
+		ss.mtx.RLock()
+		defer ss.mtx.RUnlock()
+	}
+
+	enc := json.NewEncoder(aWriter)
+	if _, err := io.WriteString(aWriter, "["); nil != err {
+		return err
+	}
+	for idx, element := range ss.data {
+		if 0 < idx {
+			if _, err := io.WriteString(aWriter, ","); nil != err {
+				return err
+			}
+		}
+		if err := enc.Encode(element); nil != err {
+			return err
+		}
+	}
+	_, err := io.WriteString(aWriter, "]")
+
+	return err
+} // WriteJSON()
+
 /* EoF */
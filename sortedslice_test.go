@@ -0,0 +1,487 @@
+/*
+Copyright ©  2024  M.Watermann, 10247 Berlin, Germany
+
+		All rights reserved
+	EMail : <support@mwat.de>
+*/
+package sortedlists
+
+import (
+	"math"
+	"slices"
+	"sync"
+	"testing"
+	"time"
+)
+
+//lint:file-ignore ST1017 - I prefer Yoda conditions
+
+func Test_TSortedSlice_Concat_lockOrder(t *testing.T) {
+	l1 := NewSlice([]int{1, 3, 5}, true)
+	l2 := NewSlice([]int{2, 4, 6}, true)
+
+	done := make(chan struct{})
+	go func() {
+		var wg sync.WaitGroup
+		for range 1000 {
+			wg.Add(2)
+			go func() {
+				defer wg.Done()
+				l1.Concat(l2)
+			}()
+			go func() {
+				defer wg.Done()
+				l2.Concat(l1)
+			}()
+		}
+		wg.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		t.Fatal("Concat() deadlocked on concurrent opposite calls")
+	}
+	if err := l1.Validate(); nil != err {
+		t.Errorf("Validate() = %v, want nil", err)
+	}
+} // Test_TSortedSlice_Concat_lockOrder()
+
+func Test_TSortedSlice_Insert_NaN(t *testing.T) {
+	sl := NewSlice([]float64{1, 2, 3}, false)
+
+	if sl.Insert(math.NaN()) {
+		t.Error("Insert(NaN) = true, want false")
+	}
+	if got := len(sl.Data()); 3 != got {
+		t.Errorf("len(Data()) = %d, want 3", got)
+	}
+	if err := sl.Validate(); nil != err {
+		t.Errorf("Validate() = %v, want nil", err)
+	}
+} // Test_TSortedSlice_Insert_NaN()
+
+func Test_TSortedSlice_Clone_config(t *testing.T) {
+	sl := NewSlice([]float64{1, 2, 3}, true)
+	sl.SetMaxLen(3, true).SetEpsilon(0.1).SetFloatPolicy(FloatAllow)
+
+	clone := sl.Clone()
+	if !clone.IsSafe() {
+		t.Error("IsSafe() = false, want true")
+	}
+	if !clone.Contains(2.05) {
+		t.Error("Contains(2.05) = false, want true (epsilon not cloned)")
+	}
+	if !clone.Insert(math.Inf(-1)) {
+		t.Error("Insert(-Inf) = false, want true (float policy not cloned)")
+	}
+	if got := len(clone.Data()); 3 != got {
+		t.Errorf("len(Data()) = %d, want 3 (length limit not cloned)", got)
+	}
+	if got, _ := clone.PeekMax(); 2 != got {
+		t.Errorf("PeekMax() = %v, want 2 (largest element not evicted)", got)
+	}
+
+	// The original must not be affected by the clone's modification.
+	if got := sl.Data(); 3 != len(got) || 1 != got[0] {
+		t.Errorf("original Data() = %v, want [1 2 3]", got)
+	}
+} // Test_TSortedSlice_Clone_config()
+
+func Test_TSortedSlice_Concat_maxLen(t *testing.T) {
+	tests := []struct {
+		name        string
+		data        []int
+		other       []int
+		dropLargest bool
+		wantAdded   int
+		wantData    []int
+	}{
+		{"all evicted", []int{5, 6}, []int{7, 8}, true, 0, []int{5, 6}},
+		{"some evicted", []int{5, 8}, []int{6, 7}, true, 1, []int{5, 6}},
+		{"old evicted", []int{5, 6}, []int{7, 8}, false, 2, []int{7, 8}},
+		{"duplicates", []int{5, 6}, []int{6, 9}, true, 0, []int{5, 6}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sl := NewSlice(tt.data, false).SetMaxLen(2, tt.dropLargest)
+			if got := sl.Concat(NewSlice(tt.other, false)); tt.wantAdded != got {
+				t.Errorf("Concat() = %d, want %d", got, tt.wantAdded)
+			}
+			if got := sl.Data(); !slices.Equal(tt.wantData, got) {
+				t.Errorf("Data() = %v, want %v", got, tt.wantData)
+			}
+		})
+	}
+} // Test_TSortedSlice_Concat_maxLen()
+
+func Test_TSortedSlice_Insert(t *testing.T) {
+	tests := []struct {
+		name    string
+		data    []int
+		element int
+		want    bool
+		wantRes []int
+	}{
+		{"empty list", []int{}, 5, true, []int{5}},
+		{"first", []int{2, 3}, 1, true, []int{1, 2, 3}},
+		{"last", []int{1, 2}, 3, true, []int{1, 2, 3}},
+		{"middle", []int{1, 3}, 2, true, []int{1, 2, 3}},
+		{"duplicate", []int{1, 2, 3}, 2, false, []int{1, 2, 3}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sl := NewSlice(tt.data, false)
+			if got := sl.Insert(tt.element); tt.want != got {
+				t.Errorf("Insert(%d) = %v, want %v", tt.element, got, tt.want)
+			}
+			if got := sl.Data(); !slices.Equal(tt.wantRes, got) {
+				t.Errorf("Data() = %v, want %v", got, tt.wantRes)
+			}
+		})
+	}
+} // Test_TSortedSlice_Insert()
+
+func Test_TSortedSlice_Delete_clearsSlot(t *testing.T) {
+	// Elements must be `cmp.Ordered`, so pointers can't be used; strings
+	// reference their bytes and thus serve the same purpose here.
+	sl := NewSlice([]string{"alpha", "beta", "gamma"}, false)
+
+	if !sl.Delete("beta") {
+		t.Fatal("Delete(beta) = false, want true")
+	}
+	backing := sl.data[:3] // includes the freed slot
+	if "" != backing[2] {
+		t.Errorf("freed slot = %q, want zero value", backing[2])
+	}
+	if got := sl.Data(); !slices.Equal([]string{"alpha", "gamma"}, got) {
+		t.Errorf("Data() = %v, want [alpha gamma]", got)
+	}
+} // Test_TSortedSlice_Delete_clearsSlot()
+
+func Test_TSortedSlice_Delete(t *testing.T) {
+	tests := []struct {
+		name    string
+		data    []int
+		element int
+		want    bool
+		wantRes []int
+	}{
+		{"empty list", []int{}, 1, false, []int{}},
+		{"single element", []int{1}, 1, true, []int{}},
+		{"first", []int{1, 2, 3}, 1, true, []int{2, 3}},
+		{"last", []int{1, 2, 3}, 3, true, []int{1, 2}},
+		{"middle", []int{1, 2, 3}, 2, true, []int{1, 3}},
+		{"not found", []int{1, 3}, 2, false, []int{1, 3}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sl := NewSlice(tt.data, false)
+			oldCap := cap(sl.data)
+			if got := sl.Delete(tt.element); tt.want != got {
+				t.Errorf("Delete(%d) = %v, want %v", tt.element, got, tt.want)
+			}
+			if got := sl.Data(); !slices.Equal(tt.wantRes, got) {
+				t.Errorf("Data() = %v, want %v", got, tt.wantRes)
+			}
+			if got := cap(sl.data); oldCap != got {
+				t.Errorf("cap() = %d, want %d", got, oldCap)
+			}
+		})
+	}
+} // Test_TSortedSlice_Delete()
+
+func Test_TSortedSlice_SetFloatPolicy(t *testing.T) {
+	nan, posInf, negInf := math.NaN(), math.Inf(1), math.Inf(-1)
+	tests := []struct {
+		name    string
+		policy  TFloatPolicy
+		element float64
+		want    bool
+		wantRes []float64
+	}{
+		{"reject NaN", FloatReject, nan, false, []float64{1, 2}},
+		{"reject +Inf", FloatReject, posInf, false, []float64{1, 2}},
+		{"reject -Inf", FloatReject, negInf, false, []float64{1, 2}},
+		{"allow NaN", FloatAllow, nan, false, []float64{1, 2}},
+		{"allow +Inf", FloatAllow, posInf, true, []float64{1, 2, posInf}},
+		{"allow -Inf", FloatAllow, negInf, true, []float64{negInf, 1, 2}},
+		{"clamp NaN", FloatClamp, nan, false, []float64{1, 2}},
+		{"clamp +Inf", FloatClamp, posInf, true, []float64{1, 2, math.MaxFloat64}},
+		{"clamp -Inf", FloatClamp, negInf, true, []float64{-math.MaxFloat64, 1, 2}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sl := NewSlice([]float64{1, 2}, false).SetFloatPolicy(tt.policy)
+			if got := sl.Insert(tt.element); tt.want != got {
+				t.Errorf("Insert(%v) = %v, want %v", tt.element, got, tt.want)
+			}
+			if got := sl.Data(); !slices.Equal(tt.wantRes, got) {
+				t.Errorf("Data() = %v, want %v", got, tt.wantRes)
+			}
+			if err := sl.Validate(); nil != err {
+				t.Errorf("Validate() = %v, want nil", err)
+			}
+
+			// `Rename()` must obey the same policy as `Insert()`.
+			sl = NewSlice([]float64{1, 2, 3}, false).SetFloatPolicy(tt.policy)
+			if got := sl.Rename(1, tt.element); tt.want != got {
+				t.Errorf("Rename(1, %v) = %v, want %v", tt.element, got, tt.want)
+			}
+			if err := sl.Validate(); nil != err {
+				t.Errorf("Validate() = %v, want nil", err)
+			}
+		})
+	}
+} // Test_TSortedSlice_SetFloatPolicy()
+
+func Test_TSortedSlice_Insert_clampedBeyondLimit(t *testing.T) {
+	sl := NewSlice([]float64{1, 2}, false).
+		SetFloatPolicy(FloatClamp).
+		SetMaxLen(2, true)
+
+	if sl.Insert(math.Inf(1)) {
+		t.Error("Insert(+Inf) = true, want false (clamped value evicted)")
+	}
+	if got := sl.Data(); !slices.Equal([]float64{1, 2}, got) {
+		t.Errorf("Data() = %v, want [1 2]", got)
+	}
+} // Test_TSortedSlice_Insert_clampedBeyondLimit()
+
+func Test_TSortedSlice_InsertMany_policyRace(t *testing.T) {
+	sl := NewSlice([]float64{}, true)
+
+	var wg sync.WaitGroup
+	wg.Add(2)
+	go func() {
+		defer wg.Done()
+		for i := range 100 {
+			sl.InsertMany(float64(i), math.Inf(1))
+		}
+	}()
+	go func() {
+		defer wg.Done()
+		for range 100 {
+			sl.SetFloatPolicy(FloatAllow)
+			sl.SetFloatPolicy(FloatReject)
+		}
+	}()
+	wg.Wait()
+
+	if err := sl.Validate(); nil != err {
+		t.Errorf("Validate() = %v, want nil", err)
+	}
+} // Test_TSortedSlice_InsertMany_policyRace()
+
+func Test_TSortedSlice_Reset(t *testing.T) {
+	sl := NewSlice([]float64{7, 8}, false).
+		SetFloatPolicy(FloatAllow).
+		SetMaxLen(5, true)
+
+	sl.Reset([]float64{3, math.Inf(1), 1, math.NaN(), 3, math.Inf(-1)}, true)
+	if got := sl.Data(); !slices.Equal([]float64{1, 3}, got) {
+		t.Errorf("Data() = %v, want [1 3]", got)
+	}
+	if !sl.IsSafe() {
+		t.Error("IsSafe() = false, want true")
+	}
+	if sl.Insert(math.Inf(1)) {
+		t.Error("Insert(+Inf) = true, want false (float policy not reset)")
+	}
+} // Test_TSortedSlice_Reset()
+
+func Test_TSortedSlice_InsertSorted(t *testing.T) {
+	tests := []struct {
+		name        string
+		data        []int
+		sorted      []int
+		maxLen      int
+		dropLargest bool
+		want        int
+		wantRes     []int
+	}{
+		{"empty list", []int{}, []int{1, 2}, 0, false, 2, []int{1, 2}},
+		{"interleaved", []int{1, 4, 7}, []int{2, 4, 8}, 0, false, 2, []int{1, 2, 4, 7, 8}},
+		{"all present", []int{1, 2}, []int{1, 2}, 0, false, 0, []int{1, 2}},
+		{"unsorted input", []int{5}, []int{3, 1, 3}, 0, false, 2, []int{1, 3, 5}},
+		{"new evicted", []int{5, 6}, []int{7, 8}, 2, true, 0, []int{5, 6}},
+		{"old evicted", []int{5, 6}, []int{7, 8}, 2, false, 2, []int{7, 8}},
+		{"some evicted", []int{5, 8}, []int{6, 9}, 3, true, 1, []int{5, 6, 8}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sl := NewSlice(tt.data, false).SetMaxLen(tt.maxLen, tt.dropLargest)
+			if got := sl.InsertSorted(tt.sorted); tt.want != got {
+				t.Errorf("InsertSorted() = %d, want %d", got, tt.want)
+			}
+			if got := sl.Data(); !slices.Equal(tt.wantRes, got) {
+				t.Errorf("Data() = %v, want %v", got, tt.wantRes)
+			}
+		})
+	}
+} // Test_TSortedSlice_InsertSorted()
+
+func Test_TSortedSlice_InsertSorted_policyRace(t *testing.T) {
+	sl := NewSlice([]float64{}, true)
+
+	var wg sync.WaitGroup
+	wg.Add(2)
+	go func() {
+		defer wg.Done()
+		for i := range 100 {
+			sl.InsertSorted([]float64{float64(i), math.Inf(1)})
+		}
+	}()
+	go func() {
+		defer wg.Done()
+		for range 100 {
+			sl.SetFloatPolicy(FloatAllow)
+			sl.SetFloatPolicy(FloatReject)
+		}
+	}()
+	wg.Wait()
+
+	if err := sl.Validate(); nil != err {
+		t.Errorf("Validate() = %v, want nil", err)
+	}
+} // Test_TSortedSlice_InsertSorted_policyRace()
+
+func Test_TSortedSlice_Rename_maxLen(t *testing.T) {
+	tests := []struct {
+		name        string
+		old, new    int
+		dropLargest bool
+		want        bool
+		wantRes     []int
+	}{
+		{"missing, new evicted", 99, 5, true, false, []int{1, 2, 3}},
+		{"missing, old evicted", 99, 5, false, true, []int{2, 3, 5}},
+		{"present", 2, 5, true, true, []int{1, 3, 5}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sl := NewSlice([]int{1, 2, 3}, false).SetMaxLen(3, tt.dropLargest)
+			if got := sl.Rename(tt.old, tt.new); tt.want != got {
+				t.Errorf("Rename(%d, %d) = %v, want %v", tt.old, tt.new, got, tt.want)
+			}
+			if got := sl.Data(); !slices.Equal(tt.wantRes, got) {
+				t.Errorf("Data() = %v, want %v", got, tt.wantRes)
+			}
+		})
+	}
+} // Test_TSortedSlice_Rename_maxLen()
+
+func Test_Collect_float(t *testing.T) {
+	nan, posInf, negInf := math.NaN(), math.Inf(1), math.Inf(-1)
+	src := []float64{2, nan, posInf, 1, negInf, 2}
+
+	sl := Collect(slices.Values(src), false)
+	if got, want := sl.Data(), []float64{1, 2}; !slices.Equal(want, got) {
+		t.Errorf("Collect() = %v, want %v", got, want)
+	}
+	if err := sl.Validate(); nil != err {
+		t.Errorf("Validate() = %v, want nil", err)
+	}
+} // Test_Collect_float()
+
+func Test_TSortedSlice_Concat_floatPolicy(t *testing.T) {
+	nan, posInf, negInf := math.NaN(), math.Inf(1), math.Inf(-1)
+	tests := []struct {
+		name    string
+		policy  TFloatPolicy
+		want    int
+		wantRes []float64
+	}{
+		{"reject", FloatReject, 1, []float64{1, 2, 3}},
+		{"allow", FloatAllow, 3, []float64{negInf, 1, 2, 3, posInf}},
+		{"clamp", FloatClamp, 3, []float64{-math.MaxFloat64, 1, 2, 3, math.MaxFloat64}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// The source list accepts everything ...
+			src := NewSlice([]float64{nan, negInf, 2, 3, posInf}, false)
+			// ... while the destination applies its own policy.
+			sl := NewSlice([]float64{1, 2}, false).SetFloatPolicy(tt.policy)
+
+			if got := sl.Concat(src); tt.want != got {
+				t.Errorf("Concat() = %d, want %d", got, tt.want)
+			}
+			if got := sl.Data(); !slices.Equal(tt.wantRes, got) {
+				t.Errorf("Data() = %v, want %v", got, tt.wantRes)
+			}
+			if err := sl.Validate(); nil != err {
+				t.Errorf("Validate() = %v, want nil", err)
+			}
+		})
+	}
+} // Test_TSortedSlice_Concat_floatPolicy()
+
+func Test_TSortedSlice_SplitAt_config(t *testing.T) {
+	sl := NewSlice([]float64{1, 2, 3, 4, 5, 6}, true)
+	sl.SetMaxLen(6, true).SetEpsilon(0.1).SetFloatPolicy(FloatClamp)
+
+	lower, upper := sl.SplitAt(4)
+	tests := []struct {
+		name    string
+		half    *TSortedSlice[float64]
+		inside  float64
+		wantRes []float64
+	}{
+		{"lower", lower, 2.05, []float64{-math.MaxFloat64, 1, 2, 3, 10, 20}},
+		{"upper", upper, 5.05, []float64{-math.MaxFloat64, 4, 5, 6, 10, 20}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if !tt.half.IsSafe() {
+				t.Error("IsSafe() = false, want true")
+			}
+			if !tt.half.Contains(tt.inside) {
+				t.Errorf("Contains(%v) = false, want true (epsilon not copied)", tt.inside)
+			}
+			if !tt.half.Insert(math.Inf(-1)) {
+				t.Error("Insert(-Inf) = false, want true (float policy not copied)")
+			}
+			// Fill the half beyond the length limit of six elements.
+			tt.half.InsertMany(10, 20, 30, 40)
+			if got := tt.half.Data(); !slices.Equal(tt.wantRes, got) {
+				t.Errorf("Data() = %v, want %v (length limit not copied)", got, tt.wantRes)
+			}
+		})
+	}
+} // Test_TSortedSlice_SplitAt_config()
+
+func Test_TSortedSlice_range_NaN(t *testing.T) {
+	nan := math.NaN()
+	tests := []struct {
+		name       string
+		low, high  float64
+		wantHas    bool
+		wantPopped []float64
+	}{
+		{"NaN low", nan, 3, false, []float64{}},
+		{"NaN high", 1, nan, false, []float64{}},
+		{"NaN both", nan, nan, false, []float64{}},
+		{"inverted", 3, 1, false, []float64{}},
+		{"regular", 1, 2, true, []float64{1, 2}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sl := NewSlice([]float64{1, 2, 3}, false)
+			if got := sl.HasRange(tt.low, tt.high); tt.wantHas != got {
+				t.Errorf("HasRange() = %v, want %v", got, tt.wantHas)
+			}
+			if got := sl.Snapshot().Range(tt.low, tt.high); !slices.Equal(tt.wantPopped, got) {
+				t.Errorf("Snapshot().Range() = %v, want %v", got, tt.wantPopped)
+			}
+			if got := sl.PopRange(tt.low, tt.high); !slices.Equal(tt.wantPopped, got) {
+				t.Errorf("PopRange() = %v, want %v", got, tt.wantPopped)
+			}
+			if got, want := len(sl.Data()), 3-len(tt.wantPopped); want != got {
+				t.Errorf("len(Data()) = %d, want %d", got, want)
+			}
+		})
+	}
+} // Test_TSortedSlice_range_NaN()
+
+/* EoF */